@@ -0,0 +1,106 @@
+package qbittorrent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+)
+
+const defaultAddWaitPollInterval = 500 * time.Millisecond
+
+// AddWaitOption configures how AddAndWait waits for the added torrents
+type AddWaitOption struct {
+	PollInterval time.Duration // interval between two polls of the added torrents, default 500ms
+	WaitMetadata bool          // also wait until the metadata of magnet links is received
+}
+
+// AddedTorrent is a torrent added by AddAndWait
+type AddedTorrent struct {
+	Info     *TorrentInfo
+	Contents []*TorrentContent // empty if the metadata was not waited for and is not received yet
+}
+
+// AddAndWait add the torrents and wait until all of them appear on the server, and with
+// wait.WaitMetadata until their metadata is received. Only torrent files and magnet links can be
+// waited for, the hashes of torrents added by http urls are unknown. The torrents are returned in
+// the order they were submitted, ctx bounds the whole wait.
+func AddAndWait(ctx context.Context, t Torrent, opt *TorrentAddOption, wait *AddWaitOption) ([]*AddedTorrent, error) {
+	var option AddWaitOption
+	if wait != nil {
+		option = *wait
+	}
+	if option.PollInterval <= 0 {
+		option.PollInterval = defaultAddWaitPollInterval
+	}
+
+	hashes, err := t.AddNewTorrentContext(ctx, opt)
+	if err != nil {
+		return nil, err
+	}
+	if len(hashes) == 0 {
+		return nil, errors.New("the hashes of the added torrents are unknown, only torrent files and magnet links can be waited for")
+	}
+	var unique []string
+	for _, hash := range hashes {
+		if !slices.Contains(unique, hash) {
+			unique = append(unique, hash)
+		}
+	}
+
+	infos, err := waitTorrents(ctx, t, unique, &option)
+	if err != nil {
+		return nil, err
+	}
+	var added = make([]*AddedTorrent, 0, len(unique))
+	for _, hash := range unique {
+		contents, err := t.GetContentsContext(ctx, hash)
+		if err != nil {
+			return nil, err
+		}
+		added = append(added, &AddedTorrent{Info: infos[hash], Contents: contents})
+	}
+	return added, nil
+}
+
+// waitTorrents poll the torrents until all of them are ready
+func waitTorrents(ctx context.Context, t Torrent, hashes []string, opt *AddWaitOption) (map[string]*TorrentInfo, error) {
+	var ticker = time.NewTicker(opt.PollInterval)
+	defer ticker.Stop()
+	for {
+		torrents, err := t.GetTorrentsContext(ctx, &TorrentOption{Hashes: hashes})
+		if err != nil {
+			return nil, err
+		}
+		var infos = make(map[string]*TorrentInfo, len(torrents))
+		var pending []string
+		for _, torrent := range torrents {
+			infos[torrent.Hash] = torrent
+		}
+		for _, hash := range hashes {
+			if info, ok := infos[hash]; !ok || (opt.WaitMetadata && !hasMetadata(info)) {
+				pending = append(pending, hash)
+			}
+		}
+		if len(pending) == 0 {
+			return infos, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("wait for torrents %v: %w", pending, ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
+
+// hasMetadata reports whether the metadata of the torrent is received, has_metadata is only
+// reported by qBittorrent 5.0+, the older versions are checked by the state and the size, a
+// stopped magnet link is pausedDL without metadata
+func hasMetadata(info *TorrentInfo) bool {
+	if info.HasMetadata != nil {
+		return *info.HasMetadata
+	}
+	return info.State != StateMetaDL && info.State != StateForcedMetaDL && info.TotalSize > 0
+}
@@ -0,0 +1,96 @@
+package qbittorrent
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/xuthus5/qbittorrent-client-go/qbittorrent/qbittest"
+)
+
+func TestAddAndWait(t *testing.T) {
+	client, server := newTestClient(t)
+	const magnet = "magnet:?xt=urn:btih:f3edaff0a8d4933dd882f44cb0b0f762bc23b4fc&dn=album"
+
+	// the metadata is received after a few polls
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		server.UpdateTorrent("f3edaff0a8d4933dd882f44cb0b0f762bc23b4fc", func(t *qbittest.Torrent) {
+			t.State = "downloading"
+			t.Size = 300
+			t.Files = []*qbittest.File{{Name: "album/01.flac", Size: 100}, {Name: "album/02.flac", Size: 200}}
+		})
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	added, err := AddAndWait(ctx, client.Torrent(), &TorrentAddOption{URLs: []string{magnet}},
+		&AddWaitOption{PollInterval: 10 * time.Millisecond, WaitMetadata: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(added) != 1 || added[0].Info.Hash != "f3edaff0a8d4933dd882f44cb0b0f762bc23b4fc" {
+		t.Fatalf("unexpected torrents: %v", added)
+	}
+	if added[0].Info.State != StateDownloading || len(added[0].Contents) != 2 {
+		t.Fatalf("expected the metadata, got %s with %d files", added[0].Info.State, len(added[0].Contents))
+	}
+}
+
+func TestAddAndWait_Timeout(t *testing.T) {
+	client, _ := newTestClient(t)
+	const magnet = "magnet:?xt=urn:btih:f23daefbe8d24d3dd882b44cb0b4f762bc23b4fc&dn=stub"
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err := AddAndWait(ctx, client.Torrent(), &TorrentAddOption{URLs: []string{magnet}},
+		&AddWaitOption{PollInterval: 10 * time.Millisecond, WaitMetadata: true})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline exceeded, got %v", err)
+	}
+
+	// without WaitMetadata the stub is enough, the first magnet is a duplicate now
+	const other = "magnet:?xt=urn:btih:8c212779b4abde7c6bc608063a0d008b7e40ce32&dn=stub"
+	added, err := AddAndWait(context.Background(), client.Torrent(), &TorrentAddOption{URLs: []string{other}}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(added) != 1 || added[0].Info.State != StateMetaDL || len(added[0].Contents) != 0 {
+		t.Fatalf("unexpected torrents: %+v", added[0].Info)
+	}
+
+	if _, err := AddAndWait(context.Background(), client.Torrent(),
+		&TorrentAddOption{URLs: []string{"https://example.org/a.torrent"}}, nil); err == nil {
+		t.Fatal("expected an error for unknown hashes")
+	}
+
+	// a stopped magnet link is pausedDL without metadata
+	const stopped = "magnet:?xt=urn:btih:d1f0ec5b4ab3a0c5aa8e2b0f3c1d6b4cf0a6a3d2&dn=stub"
+	ctx, cancel = context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err = AddAndWait(ctx, client.Torrent(), &TorrentAddOption{URLs: []string{stopped}, Stopped: true},
+		&AddWaitOption{PollInterval: 10 * time.Millisecond, WaitMetadata: true})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline exceeded, got %v", err)
+	}
+}
+
+func TestHasMetadata(t *testing.T) {
+	yes, no := true, false
+	for _, tc := range []struct {
+		info     TorrentInfo
+		expected bool
+	}{
+		{TorrentInfo{State: StateMetaDL}, false},
+		{TorrentInfo{State: StateForcedMetaDL}, false},
+		{TorrentInfo{State: StateDownloading, TotalSize: 1024}, true},
+		{TorrentInfo{State: StatePausedDL}, false},
+		{TorrentInfo{State: StateStoppedDL, HasMetadata: &no}, false},
+		{TorrentInfo{State: StateMetaDL, HasMetadata: &yes}, true},
+	} {
+		if hasMetadata(&tc.info) != tc.expected {
+			t.Fatalf("%s: expected %v", tc.info.State, tc.expected)
+		}
+	}
+}
@@ -1,10 +1,10 @@
 package qbittorrent
 
 import (
-	"bytes"
-	"errors"
-	"fmt"
+	"context"
 	"net/http"
+	"net/url"
+	"strings"
 
 	"github.com/bytedance/sonic"
 )
@@ -12,18 +12,32 @@ import (
 type Application interface {
 	// Version get application version
 	Version() (string, error)
+	// VersionContext is the context-aware version of Version
+	VersionContext(ctx context.Context) (string, error)
 	// WebApiVersion get webapi version
 	WebApiVersion() (string, error)
+	// WebApiVersionContext is the context-aware version of WebApiVersion
+	WebApiVersionContext(ctx context.Context) (string, error)
 	// BuildInfo get build info
 	BuildInfo() (*BuildInfo, error)
+	// BuildInfoContext is the context-aware version of BuildInfo
+	BuildInfoContext(ctx context.Context) (*BuildInfo, error)
 	// Shutdown exit application
 	Shutdown() error
+	// ShutdownContext is the context-aware version of Shutdown
+	ShutdownContext(ctx context.Context) error
 	// GetPreferences get application preferences
 	GetPreferences() (*Preferences, error)
+	// GetPreferencesContext is the context-aware version of GetPreferences
+	GetPreferencesContext(ctx context.Context) (*Preferences, error)
 	// SetPreferences set application preferences
 	SetPreferences(*Preferences) error
+	// SetPreferencesContext is the context-aware version of SetPreferences
+	SetPreferencesContext(ctx context.Context, prefs *Preferences) error
 	// DefaultSavePath get default save path
 	DefaultSavePath() (string, error)
+	// DefaultSavePathContext is the context-aware version of DefaultSavePath
+	DefaultSavePathContext(ctx context.Context) (string, error)
 }
 
 type BuildInfo struct {
@@ -240,9 +254,13 @@ type Preferences struct {
 }
 
 func (c *client) Version() (string, error) {
-	apiUrl := fmt.Sprintf("%s/api/v2/app/version", c.config.Address)
+	return c.VersionContext(context.Background())
+}
+
+func (c *client) VersionContext(ctx context.Context) (string, error) {
+	apiUrl := c.apiURL("app/version", nil)
 
-	result, err := c.doRequest(&requestData{
+	result, err := c.doRequest(ctx, &requestData{
 		url: apiUrl,
 	})
 	if err != nil {
@@ -250,15 +268,19 @@ func (c *client) Version() (string, error) {
 	}
 
 	if result.code != 200 {
-		return "", errors.New("get version failed: " + string(result.body))
+		return "", newAPIError("get version failed", result)
 	}
 
 	return string(result.body), nil
 }
 
 func (c *client) WebApiVersion() (string, error) {
-	apiUrl := fmt.Sprintf("%s/api/v2/app/webapiVersion", c.config.Address)
-	result, err := c.doRequest(&requestData{
+	return c.WebApiVersionContext(context.Background())
+}
+
+func (c *client) WebApiVersionContext(ctx context.Context) (string, error) {
+	apiUrl := c.apiURL("app/webapiVersion", nil)
+	result, err := c.doRequest(ctx, &requestData{
 		url: apiUrl,
 	})
 	if err != nil {
@@ -266,15 +288,19 @@ func (c *client) WebApiVersion() (string, error) {
 	}
 
 	if result.code != 200 {
-		return "", errors.New("get version failed: " + string(result.body))
+		return "", newAPIError("get version failed", result)
 	}
 
 	return string(result.body), nil
 }
 
 func (c *client) BuildInfo() (*BuildInfo, error) {
-	apiUrl := fmt.Sprintf("%s/api/v2/app/buildInfo", c.config.Address)
-	result, err := c.doRequest(&requestData{
+	return c.BuildInfoContext(context.Background())
+}
+
+func (c *client) BuildInfoContext(ctx context.Context) (*BuildInfo, error) {
+	apiUrl := c.apiURL("app/buildInfo", nil)
+	result, err := c.doRequest(ctx, &requestData{
 		url: apiUrl,
 	})
 	if err != nil {
@@ -282,7 +308,7 @@ func (c *client) BuildInfo() (*BuildInfo, error) {
 	}
 
 	if result.code != 200 {
-		return nil, errors.New("get build info failed: " + string(result.body))
+		return nil, newAPIError("get build info failed", result)
 	}
 
 	var build = new(BuildInfo)
@@ -294,8 +320,12 @@ func (c *client) BuildInfo() (*BuildInfo, error) {
 }
 
 func (c *client) Shutdown() error {
-	apiUrl := fmt.Sprintf("%s/api/v2/app/shutdown", c.config.Address)
-	result, err := c.doRequest(&requestData{
+	return c.ShutdownContext(context.Background())
+}
+
+func (c *client) ShutdownContext(ctx context.Context) error {
+	apiUrl := c.apiURL("app/shutdown", nil)
+	result, err := c.doRequest(ctx, &requestData{
 		method: http.MethodPost,
 		url:    apiUrl,
 	})
@@ -304,15 +334,19 @@ func (c *client) Shutdown() error {
 	}
 
 	if result.code != 200 {
-		return errors.New("shutdown application failed: " + string(result.body))
+		return newAPIError("shutdown application failed", result)
 	}
 
 	return nil
 }
 
 func (c *client) GetPreferences() (*Preferences, error) {
-	apiUrl := fmt.Sprintf("%s/api/v2/app/preferences", c.config.Address)
-	result, err := c.doRequest(&requestData{
+	return c.GetPreferencesContext(context.Background())
+}
+
+func (c *client) GetPreferencesContext(ctx context.Context) (*Preferences, error) {
+	apiUrl := c.apiURL("app/preferences", nil)
+	result, err := c.doRequest(ctx, &requestData{
 		url: apiUrl,
 	})
 	if err != nil {
@@ -320,7 +354,7 @@ func (c *client) GetPreferences() (*Preferences, error) {
 	}
 
 	if result.code != 200 {
-		return nil, errors.New("get preference failed: " + string(result.body))
+		return nil, newAPIError("get preference failed", result)
 	}
 
 	var preferences = new(Preferences)
@@ -332,35 +366,41 @@ func (c *client) GetPreferences() (*Preferences, error) {
 }
 
 func (c *client) SetPreferences(prefs *Preferences) error {
-	apiUrl := fmt.Sprintf("%s/api/v2/app/setPreferences", c.config.Address)
+	return c.SetPreferencesContext(context.Background(), prefs)
+}
+
+func (c *client) SetPreferencesContext(ctx context.Context, prefs *Preferences) error {
+	apiUrl := c.apiURL("app/setPreferences", nil)
 	data, err := sonic.Marshal(prefs)
 	if err != nil {
 		return err
 	}
-	var formData bytes.Buffer
-	formData.Write([]byte("json="))
-	formData.Write(data)
+	var formData = url.Values{"json": {string(data)}}
 
-	result, err := c.doRequest(&requestData{
+	result, err := c.doRequest(ctx, &requestData{
 		method:      http.MethodPost,
 		url:         apiUrl,
 		contentType: ContentTypeFormUrlEncoded,
-		body:        &formData,
+		body:        strings.NewReader(formData.Encode()),
 	})
 	if err != nil {
 		return err
 	}
 
 	if result.code != 200 {
-		return errors.New("set preference failed: " + string(result.body))
+		return newAPIError("set preference failed", result)
 	}
 
 	return nil
 }
 
 func (c *client) DefaultSavePath() (string, error) {
-	apiUrl := fmt.Sprintf("%s/api/v2/app/defaultSavePath", c.config.Address)
-	result, err := c.doRequest(&requestData{
+	return c.DefaultSavePathContext(context.Background())
+}
+
+func (c *client) DefaultSavePathContext(ctx context.Context) (string, error) {
+	apiUrl := c.apiURL("app/defaultSavePath", nil)
+	result, err := c.doRequest(ctx, &requestData{
 		url: apiUrl,
 	})
 	if err != nil {
@@ -368,7 +408,7 @@ func (c *client) DefaultSavePath() (string, error) {
 	}
 
 	if result.code != 200 {
-		return "", errors.New("get default save path failed: " + string(result.body))
+		return "", newAPIError("get default save path failed", result)
 	}
 
 	return string(result.body), nil
@@ -0,0 +1,88 @@
+package qbittorrent
+
+import (
+	"context"
+	"errors"
+	"net/http"
+)
+
+// AuthMode how the client authenticates to the WebUI
+type AuthMode int
+
+const (
+	// AuthCookie login with Username and Password and send the SID cookie, the default mode
+	AuthCookie AuthMode = iota
+	// AuthNone send no credentials, for servers with bypass_local_auth or bypass_auth_subnet_whitelist
+	AuthNone
+	// AuthBearer send AuthToken as "Authorization: Bearer <token>", for SSO reverse proxies
+	AuthBearer
+	// AuthHeader send AuthToken in the AuthHeader header, for reverse proxies with custom auth headers
+	AuthHeader
+	// AuthBasic send Username and Password as HTTP basic auth, for reverse proxies with basic auth
+	AuthBasic
+)
+
+func (m AuthMode) String() string {
+	switch m {
+	case AuthCookie:
+		return "cookie"
+	case AuthNone:
+		return "none"
+	case AuthBearer:
+		return "bearer"
+	case AuthHeader:
+		return "header"
+	case AuthBasic:
+		return "basic"
+	}
+	return "unknown"
+}
+
+// validate check that the config has the credentials the mode needs
+func (m AuthMode) validate(cfg *Config) error {
+	switch m {
+	case AuthCookie, AuthBasic:
+		if cfg.Username == "" || cfg.Password == "" {
+			return errors.New("username or password is empty")
+		}
+	case AuthNone:
+	case AuthBearer:
+		if cfg.AuthToken == "" {
+			return errors.New("auth token is empty")
+		}
+	case AuthHeader:
+		if cfg.AuthHeader == "" || cfg.AuthToken == "" {
+			return errors.New("auth header or auth token is empty")
+		}
+	default:
+		return errors.New("unknown auth mode " + m.String())
+	}
+	return nil
+}
+
+// setAuth attach the credentials of the auth mode to the request, the cookie of AuthCookie is
+// attached by the cookie jar
+func (c *client) setAuth(request *http.Request) {
+	switch c.config.AuthMode {
+	case AuthBearer:
+		request.Header.Set("Authorization", "Bearer "+c.config.AuthToken)
+	case AuthHeader:
+		request.Header.Set(c.config.AuthHeader, c.config.AuthToken)
+	case AuthBasic:
+		request.SetBasicAuth(c.config.Username, c.config.Password)
+	}
+}
+
+// authenticate login in AuthCookie mode, the other modes have no login so the credentials are
+// checked with the webapi version request
+func (c *client) authenticate(ctx context.Context) error {
+	if c.config.AuthMode == AuthCookie {
+		return c.LoginContext(ctx)
+	}
+	if err := c.config.AuthMode.validate(c.config); err != nil {
+		return err
+	}
+	c.resetVersion()
+	_, err := c.webAPIVersion(ctx)
+	return err
+}
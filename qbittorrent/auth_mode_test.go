@@ -0,0 +1,95 @@
+package qbittorrent
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/http/httputil"
+	"net/url"
+	"sync/atomic"
+	"testing"
+
+	"github.com/xuthus5/qbittorrent-client-go/qbittorrent/qbittest"
+)
+
+func TestClient_AuthNone(t *testing.T) {
+	server := qbittest.NewServer()
+	defer server.Close()
+
+	var logins atomic.Int32
+	cfg := &Config{Address: server.URL, AuthMode: AuthNone, Middlewares: []Middleware{countLogins(&logins)}}
+	if _, err := NewClient(cfg); !IsForbidden(err) {
+		t.Fatalf("expected forbidden without bypass, got %v", err)
+	}
+
+	server.SetBypassAuth(true)
+	client, err := NewClient(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.Application().Version(); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.Authentication().Login(); err != nil {
+		t.Fatal(err)
+	}
+	if logins.Load() != 0 {
+		t.Fatalf("expected no login request, got %d", logins.Load())
+	}
+}
+
+func TestClient_AuthProxy(t *testing.T) {
+	server := qbittest.NewServer()
+	defer server.Close()
+	server.SetBypassAuth(true)
+
+	target, _ := url.Parse(server.URL)
+	upstream := httputil.NewSingleHostReverseProxy(target)
+	newProxy := func(authorized func(r *http.Request) bool) *httptest.Server {
+		proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !authorized(r) {
+				http.Error(w, "Forbidden", http.StatusForbidden)
+				return
+			}
+			upstream.ServeHTTP(w, r)
+		}))
+		t.Cleanup(proxy.Close)
+		return proxy
+	}
+
+	bearer := newProxy(func(r *http.Request) bool {
+		return r.Header.Get("Authorization") == "Bearer sso-token"
+	})
+	header := newProxy(func(r *http.Request) bool {
+		return r.Header.Get("X-Auth-Token") == "sso-token"
+	})
+	basic := newProxy(func(r *http.Request) bool {
+		username, password, ok := r.BasicAuth()
+		return ok && username == "proxy" && password == "secret"
+	})
+
+	for _, cfg := range []*Config{
+		{Address: bearer.URL, AuthMode: AuthBearer, AuthToken: "sso-token"},
+		{Address: header.URL, AuthMode: AuthHeader, AuthHeader: "X-Auth-Token", AuthToken: "sso-token"},
+		{Address: basic.URL, AuthMode: AuthBasic, Username: "proxy", Password: "secret"},
+	} {
+		client, err := NewClient(cfg)
+		if err != nil {
+			t.Fatalf("%s: %v", cfg.AuthMode, err)
+		}
+		if _, err := client.Torrent().GetTorrents(&TorrentOption{}); err != nil {
+			t.Fatalf("%s: %v", cfg.AuthMode, err)
+		}
+
+		cfg.AuthToken, cfg.Password = "wrong", "wrong"
+		if _, err := NewClient(cfg); !IsForbidden(err) {
+			t.Fatalf("%s: expected forbidden with wrong credentials, got %v", cfg.AuthMode, err)
+		}
+	}
+
+	if _, err := NewClient(&Config{Address: bearer.URL, AuthMode: AuthBearer}); err == nil {
+		t.Fatal("expected an error without token")
+	}
+	if _, err := NewClient(&Config{Address: header.URL, AuthMode: AuthHeader, AuthToken: "sso-token"}); err == nil {
+		t.Fatal("expected an error without header name")
+	}
+}
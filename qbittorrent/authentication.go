@@ -1,8 +1,8 @@
 package qbittorrent
 
 import (
+	"context"
 	"errors"
-	"fmt"
 	"net/http"
 	"net/http/cookiejar"
 	"net/url"
@@ -11,13 +11,28 @@ import (
 
 type Authentication interface {
 	// Login cookie-based authentication, after calling NewClient, do not need to call Login again,
-	// it is the default behavior
+	// it is the default behavior. the auth modes without login only check the credentials
 	Login() error
+	// LoginContext is the context-aware version of Login
+	LoginContext(ctx context.Context) error
 	// Logout deactivate cookies
 	Logout() error
+	// LogoutContext is the context-aware version of Logout
+	LogoutContext(ctx context.Context) error
+	// ExportSession export the cookies of the current session, use NewClientWithSession to reuse it
+	ExportSession() (*Session, error)
+	// ImportSession replace the cookies of the client with a saved session, the session is not verified
+	ImportSession(session *Session) error
 }
 
 func (c *client) Login() error {
+	return c.LoginContext(context.Background())
+}
+
+func (c *client) LoginContext(ctx context.Context) error {
+	if c.config.AuthMode != AuthCookie {
+		return c.authenticate(ctx)
+	}
 	if c.config.Username == "" || c.config.Password == "" {
 		return errors.New("username or password is empty")
 	}
@@ -27,19 +42,20 @@ func (c *client) Login() error {
 	formData.Set("password", c.config.Password)
 	encodedFormData := formData.Encode()
 
-	apiUrl := fmt.Sprintf("%s/api/v2/auth/login", c.config.Address)
+	apiUrl := c.apiURL("auth/login", nil)
 
-	result, err := c.doRequest(&requestData{
-		method: http.MethodPost,
-		url:    apiUrl,
-		body:   strings.NewReader(encodedFormData),
+	result, err := c.doRequest(ctx, &requestData{
+		method:      http.MethodPost,
+		url:         apiUrl,
+		body:        strings.NewReader(encodedFormData),
+		skipReLogin: true,
 	})
 	if err != nil {
 		return err
 	}
 
 	if result.code != 200 {
-		return errors.New("login failed: " + string(result.body))
+		return newAPIError("login failed", result)
 	}
 
 	if string(result.body) == "Fails." {
@@ -57,27 +73,35 @@ func (c *client) Login() error {
 		}
 	}
 
-	u, err := url.Parse(c.config.Address)
-	if err != nil {
-		return err
-	}
-	c.cookieJar.SetCookies(u, result.cookies)
+	c.cookieJar.SetCookies(c.baseURL, result.cookies)
+	c.loginGeneration.Add(1)
+	c.saveSession(ctx)
+
+	// detect the webapi version of the server, it may have been upgraded since the last login.
+	// a failure is not fatal, the version is queried again when needed
+	c.resetVersion()
+	_, _ = c.webAPIVersion(ctx)
 
 	return nil
 }
 
 func (c *client) Logout() error {
-	apiUrl := fmt.Sprintf("%s/api/v2/auth/logout", c.config.Address)
-	result, err := c.doRequest(&requestData{
-		method: http.MethodPost,
-		url:    apiUrl,
+	return c.LogoutContext(context.Background())
+}
+
+func (c *client) LogoutContext(ctx context.Context) error {
+	apiUrl := c.apiURL("auth/logout", nil)
+	result, err := c.doRequest(ctx, &requestData{
+		method:      http.MethodPost,
+		url:         apiUrl,
+		skipReLogin: true,
 	})
 	if err != nil {
 		return err
 	}
 
 	if result.code != 200 {
-		return errors.New("logout failed: " + string(result.body))
+		return newAPIError("logout failed", result)
 	}
 
 	return nil
@@ -1,6 +1,13 @@
 package qbittorrent
 
-import "testing"
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
 
 func TestClient_Login(t *testing.T) {
 	if err := c.Authentication().Login(); err != nil {
@@ -17,3 +24,62 @@ func TestClient_Logout(t *testing.T) {
 		t.Fatal(err)
 	}
 }
+
+func TestClient_AutoReLogin(t *testing.T) {
+	var logins, sid atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v2/auth/login":
+			logins.Add(1)
+			http.SetCookie(w, &http.Cookie{Name: "SID", Value: strconv.Itoa(int(sid.Add(1))), Path: "/"})
+			_, _ = w.Write([]byte("Ok."))
+		case "/api/v2/app/version":
+			// only the latest session is valid
+			cookie, err := r.Cookie("SID")
+			if err != nil || cookie.Value != strconv.Itoa(int(sid.Load())) || logins.Load() < 2 {
+				w.WriteHeader(http.StatusForbidden)
+				_, _ = w.Write([]byte("Forbidden"))
+				return
+			}
+			_, _ = w.Write([]byte("v4.6.5"))
+		}
+	}))
+	defer server.Close()
+
+	var reAuthenticated atomic.Int32
+	cli, err := NewClient(&Config{
+		Address:  server.URL,
+		Username: "admin",
+		Password: "adminadmin",
+		OnReAuthenticate: func(err error) {
+			if err != nil {
+				t.Error(err)
+			}
+			reAuthenticated.Add(1)
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			version, err := cli.Application().Version()
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			if version != "v4.6.5" {
+				t.Errorf("unexpected version %s", version)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if logins.Load() != 2 || reAuthenticated.Load() != 1 {
+		t.Fatalf("expected a single re-login, got logins=%d re-authenticated=%d", logins.Load(), reAuthenticated.Load())
+	}
+}
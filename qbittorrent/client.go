@@ -1,5 +1,7 @@
 package qbittorrent
 
+import "context"
+
 // Client represents a qBittorrent client
 type Client interface {
 	// Authentication auth qBittorrent client
@@ -18,15 +20,56 @@ type Client interface {
 	Search() Search
 	// RSS api for rss
 	RSS() RSS
+	// Capabilities get the version-dependent features of the server, the webapi version is detected
+	// after login and cached
+	Capabilities() (*Capabilities, error)
+	// CapabilitiesContext is the context-aware version of Capabilities
+	CapabilitiesContext(ctx context.Context) (*Capabilities, error)
 }
 
+// NewClient create a qBittorrent client and login
 func NewClient(cfg *Config) (Client, error) {
-	var c = &client{config: cfg, clientPool: newClientPool(cfg.ConnectionMaxIdles, cfg.ConnectionTimeout)}
-	if err := c.Authentication().Login(); err != nil {
+	return NewClientContext(context.Background(), cfg)
+}
+
+// NewClientContext create a qBittorrent client and login, the ctx is only used for the login request.
+// if Config.SessionStore is set, the saved session is reused and login only happens if it is rejected
+// or cannot be loaded. the auth modes without login check the credentials with a webapi version
+// request instead
+func NewClientContext(ctx context.Context, cfg *Config) (Client, error) {
+	if cfg.SessionStore != nil && cfg.AuthMode == AuthCookie {
+		session, err := cfg.SessionStore.LoadSession(ctx)
+		if err != nil {
+			// a corrupted session must not prevent login, the next login overwrites it
+			if cfg.Logger != nil {
+				cfg.Logger.WarnContext(ctx, "qbittorrent load session failed", "error", err.Error())
+			}
+			session = nil
+		}
+		return NewClientWithSessionContext(ctx, cfg, session)
+	}
+	c, err := newClient(cfg)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.authenticate(ctx); err != nil {
 		return nil, err
 	}
-	if cfg.RefreshCookie {
+	if cfg.RefreshCookie && cfg.AuthMode == AuthCookie {
 		go c.refreshCookie()
 	}
 	return c, nil
 }
+
+// newClient validate the config and create a client without login
+func newClient(cfg *Config) (*client, error) {
+	baseURL, socket, err := parseAddress(cfg.Address)
+	if err != nil {
+		return nil, err
+	}
+	pool, err := newClientPool(cfg, socket)
+	if err != nil {
+		return nil, err
+	}
+	return &client{config: cfg, clientPool: pool, baseURL: baseURL}, nil
+}
@@ -1,20 +1,25 @@
 package qbittorrent
 
 import (
+	"bytes"
+	"context"
 	"fmt"
 	"io"
 	"net/http"
 	"net/http/cookiejar"
-	"net/http/httputil"
 	"net/url"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 )
 
 type responseResult struct {
-	code    int
-	body    []byte
-	cookies []*http.Cookie
+	code     int
+	body     []byte
+	cookies  []*http.Cookie
+	method   string
+	endpoint string
 }
 
 type requestData struct {
@@ -22,7 +27,12 @@ type requestData struct {
 	url         string
 	contentType string
 	body        io.Reader
-	debug       bool
+	// newBody returns a new body for every attempt instead of body, the body is streamed and
+	// not buffered, contentLength is its length
+	newBody       func() io.Reader
+	contentLength int64
+	// skipReLogin do not login again when the response is 403, used by the auth api
+	skipReLogin bool
 }
 
 var _ Client = (*client)(nil)
@@ -30,7 +40,16 @@ var _ Client = (*client)(nil)
 type client struct {
 	config     *Config
 	clientPool *clientPool
-	cookieJar  *cookiejar.Jar
+	// baseURL parsed Config.Address, see apiURL
+	baseURL   *url.URL
+	cookieJar *cookiejar.Jar
+	// loginMu serializes re-login so that concurrent requests do not stampede the auth api
+	loginMu sync.Mutex
+	// loginGeneration increased after every successful login
+	loginGeneration atomic.Uint64
+	// versionMu guards version, the webapi version detected after login
+	versionMu sync.Mutex
+	version   *Version
 }
 
 func (c *client) Authentication() Authentication {
@@ -65,67 +84,116 @@ func (c *client) RSS() RSS {
 	return c
 }
 
-// doRequest send request
-func (c *client) doRequest(data *requestData) (*responseResult, error) {
+// doRequest send request, the ctx controls cancellation and deadline of the request.
+// if the session is expired, doRequest will login again and replay the request once
+func (c *client) doRequest(ctx context.Context, data *requestData) (*responseResult, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
 	if data.method == "" {
 		data.method = "GET"
 	}
 	if data.contentType == "" {
 		data.contentType = ContentTypeFormUrlEncoded
 	}
-	request, err := http.NewRequest(data.method, data.url, data.body)
+	// buffer the body so that the request can be replayed
+	var body []byte
+	if data.body != nil && data.newBody == nil {
+		var err error
+		if body, err = io.ReadAll(data.body); err != nil {
+			return nil, err
+		}
+	}
+
+	generation := c.loginGeneration.Load()
+	result, err := c.sendWithRetry(ctx, data, body)
 	if err != nil {
 		return nil, err
 	}
+	if result.code != http.StatusForbidden || data.skipReLogin || c.config.DisableAutoReLogin ||
+		c.config.AuthMode != AuthCookie {
+		return result, nil
+	}
+
+	if err := c.reLogin(ctx, generation); err != nil {
+		return nil, fmt.Errorf("re-login failed: %w", err)
+	}
+	return c.sendWithRetry(ctx, data, body)
+}
+
+// sendRequest send a single http request
+func (c *client) sendRequest(ctx context.Context, data *requestData, body []byte) (*responseResult, error) {
+	var reader io.Reader
+	if data.newBody != nil {
+		reader = data.newBody()
+	} else if body != nil {
+		reader = bytes.NewReader(body)
+	}
+	request, err := http.NewRequestWithContext(ctx, data.method, data.url, reader)
+	if err != nil {
+		if closer, ok := reader.(io.Closer); ok {
+			_ = closer.Close()
+		}
+		return nil, err
+	}
+	if data.newBody != nil {
+		request.ContentLength = data.contentLength
+	}
 
 	request.Header.Set("Content-Type", data.contentType)
 	for key, value := range c.config.CustomHeaders {
 		request.Header.Set(key, value)
 	}
+	c.setAuth(request)
 	hc := c.clientPool.GetClient()
 	defer c.clientPool.ReleaseClient(hc)
 	if c.cookieJar != nil {
 		hc.Jar = c.cookieJar
 	}
 
-	if data.debug {
-		dumpRequest, err := httputil.DumpRequest(request, true)
-		if err != nil {
-			return nil, err
-		}
-		fmt.Println(string(dumpRequest))
-	}
-
+	var start = time.Now()
 	resp, err := hc.Do(request)
 	if err != nil {
+		c.logRequest(ctx, request, body, nil, nil, time.Since(start), err)
 		return nil, err
 	}
 	defer resp.Body.Close()
-	if data.debug {
-		response, err := httputil.DumpResponse(resp, true)
-		if err != nil {
-			return nil, err
-		}
-		fmt.Println(string(response))
-	}
 
 	readAll, err := io.ReadAll(resp.Body)
+	c.logRequest(ctx, request, body, resp, readAll, time.Since(start), err)
 	if err != nil {
 		return nil, err
 	}
 
-	return &responseResult{code: resp.StatusCode, body: readAll, cookies: resp.Cookies()}, nil
+	return &responseResult{
+		code:     resp.StatusCode,
+		body:     readAll,
+		cookies:  resp.Cookies(),
+		method:   request.Method,
+		endpoint: request.URL.Path,
+	}, nil
+}
+
+// reLogin login again after the session expired, generation is the login generation observed
+// before the failed request, if another request has already logged in since then, reLogin is a no-op
+func (c *client) reLogin(ctx context.Context, generation uint64) error {
+	c.loginMu.Lock()
+	defer c.loginMu.Unlock()
+	if c.loginGeneration.Load() != generation {
+		return nil
+	}
+	err := c.LoginContext(ctx)
+	if c.config.OnReAuthenticate != nil {
+		c.config.OnReAuthenticate(err)
+	}
+	return err
 }
 
 func (c *client) cookies() (string, error) {
 	if c.cookieJar == nil {
 		return "", ErrNotLogin
 	}
-	u, err := url.Parse(c.config.Address)
-	if err != nil {
-		return "", err
-	}
-	cookies := c.cookieJar.Cookies(u)
+	cookies := c.cookieJar.Cookies(c.baseURL)
 	if len(cookies) == 0 {
 		return "", ErrNotLogin
 	}
@@ -143,8 +211,9 @@ func (c *client) refreshCookie() {
 	}
 	var ticker = time.NewTicker(c.config.RefreshIntervals)
 	for range ticker.C {
-		if err := c.Authentication().Logout(); err != nil {
-			// todo
+		err := c.Authentication().Login()
+		if c.config.OnReAuthenticate != nil {
+			c.config.OnReAuthenticate(err)
 		}
 	}
 }
@@ -1,45 +1,105 @@
 package qbittorrent
 
 import (
-	"crypto/tls"
+	"context"
+	"errors"
 	"net"
 	"net/http"
 	"sync"
 	"time"
 )
 
+// Middleware wraps the http.RoundTripper used to send the requests, it can be used to add tracing,
+// metrics, auth headers or to replace the transport in tests
+type Middleware func(next http.RoundTripper) http.RoundTripper
+
+// RoundTripperFunc is an adapter to allow the use of ordinary functions as http.RoundTripper
+type RoundTripperFunc func(*http.Request) (*http.Response, error)
+
+// RoundTrip calls f(req)
+func (f RoundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
 // clientPool defines a pool of HTTP clients
 type clientPool struct {
 	// pool store http.Client instances
 	*sync.Pool
 }
 
-// newClientPool creates and returns a new clientPool
-func newClientPool(maxIdle int, timeout time.Duration) *clientPool {
-	if maxIdle == 0 {
-		maxIdle = 128
+// newClientPool creates and returns a new clientPool, all clients share the same transport,
+// socket is the path of the unix socket to dial, empty for tcp
+func newClientPool(cfg *Config, socket string) (*clientPool, error) {
+	transport, err := newTransport(cfg, socket)
+	if err != nil {
+		return nil, err
 	}
+	var timeout = cfg.ConnectionTimeout
 	if timeout == 0 {
 		timeout = time.Second * 3
 	}
 	return &clientPool{
 		Pool: &sync.Pool{
 			New: func() any {
+				if cfg.HTTPClient != nil {
+					// copy the client, the cookie jar of the pooled clients is replaced per request
+					hc := *cfg.HTTPClient
+					hc.Transport = transport
+					return &hc
+				}
 				return &http.Client{
-					Transport: &http.Transport{
-						Proxy: http.ProxyFromEnvironment,
-						DialContext: (&net.Dialer{
-							Timeout:   30 * time.Second,
-							KeepAlive: 30 * time.Second,
-						}).DialContext,
-						TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
-						MaxIdleConns:    maxIdle,
-					},
-					Timeout: timeout,
+					Transport: transport,
+					Timeout:   timeout,
 				}
 			},
 		},
+	}, nil
+}
+
+// newTransport build the transport of the config, the middlewares are applied in order so that the
+// first middleware is the outermost
+func newTransport(cfg *Config, socket string) (http.RoundTripper, error) {
+	var transport http.RoundTripper
+	switch {
+	case cfg.HTTPClient != nil && cfg.HTTPClient.Transport != nil:
+		if socket != "" {
+			return nil, errors.New("unix socket address can not be used with a custom transport")
+		}
+		transport = cfg.HTTPClient.Transport
+	case cfg.HTTPClient != nil && socket == "":
+		transport = http.DefaultTransport
+	default:
+		tlsConfig, err := newTLSConfig(cfg.TLS)
+		if err != nil {
+			return nil, err
+		}
+		var maxIdle = cfg.ConnectionMaxIdles
+		if maxIdle == 0 {
+			maxIdle = 128
+		}
+		var dialer = &net.Dialer{
+			Timeout:   30 * time.Second,
+			KeepAlive: 30 * time.Second,
+		}
+		var httpTransport = &http.Transport{
+			Proxy:           http.ProxyFromEnvironment,
+			DialContext:     dialer.DialContext,
+			TLSClientConfig: tlsConfig,
+			MaxIdleConns:    maxIdle,
+		}
+		if socket != "" {
+			// every request goes to the socket whatever the host of the url is
+			httpTransport.Proxy = nil
+			httpTransport.DialContext = func(ctx context.Context, _, _ string) (net.Conn, error) {
+				return dialer.DialContext(ctx, "unix", socket)
+			}
+		}
+		transport = httpTransport
+	}
+	for i := len(cfg.Middlewares) - 1; i >= 0; i-- {
+		transport = cfg.Middlewares[i](transport)
 	}
+	return transport, nil
 }
 
 // GetClient retrieves a http.Client from the pool
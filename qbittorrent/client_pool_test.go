@@ -0,0 +1,90 @@
+package qbittorrent
+
+import (
+	"net/http"
+	"net/http/cookiejar"
+	"slices"
+	"sync/atomic"
+	"testing"
+
+	"github.com/xuthus5/qbittorrent-client-go/qbittorrent/qbittest"
+)
+
+func TestClient_Middlewares(t *testing.T) {
+	server := qbittest.NewServer()
+	defer server.Close()
+
+	var order []string
+	var requests atomic.Int32
+	middleware := func(name string) Middleware {
+		return func(next http.RoundTripper) http.RoundTripper {
+			return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+				if requests.Load() == 0 {
+					order = append(order, name)
+				}
+				req.Header.Set("X-Middleware", name)
+				return next.RoundTrip(req)
+			})
+		}
+	}
+	counter := func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			requests.Add(1)
+			return next.RoundTrip(req)
+		})
+	}
+
+	client, err := NewClient(&Config{
+		Address:     server.URL,
+		Username:    qbittest.DefaultUsername,
+		Password:    qbittest.DefaultPassword,
+		Middlewares: []Middleware{middleware("outer"), middleware("inner"), counter},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(order, []string{"outer", "inner"}) {
+		t.Fatalf("expected middlewares in order, got %v", order)
+	}
+
+	before := requests.Load()
+	if _, err := client.Application().Version(); err != nil {
+		t.Fatal(err)
+	}
+	if requests.Load() != before+1 {
+		t.Fatalf("expected 1 request through the middlewares, got %d", requests.Load()-before)
+	}
+}
+
+func TestClient_HTTPClient(t *testing.T) {
+	server := qbittest.NewServer()
+	defer server.Close()
+
+	var requests atomic.Int32
+	jar, _ := cookiejar.New(nil)
+	hc := &http.Client{
+		Jar: jar,
+		Transport: RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			requests.Add(1)
+			return http.DefaultTransport.RoundTrip(req)
+		}),
+	}
+	client, err := NewClient(&Config{
+		Address:    server.URL,
+		Username:   qbittest.DefaultUsername,
+		Password:   qbittest.DefaultPassword,
+		HTTPClient: hc,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.Application().Version(); err != nil {
+		t.Fatal(err)
+	}
+	if requests.Load() == 0 {
+		t.Fatal("expected the requests to use the custom transport")
+	}
+	if hc.Jar != jar {
+		t.Fatal("expected the jar of the custom client to be left untouched")
+	}
+}
@@ -1,26 +1,46 @@
 package qbittorrent
 
 import (
+	"context"
+	"errors"
 	"net/url"
 	"testing"
 	"time"
+
+	"github.com/xuthus5/qbittorrent-client-go/qbittorrent/qbittest"
 )
 
 var (
-	c Client
+	c      Client
+	server *qbittest.Server
 )
 
 func init() {
+	server = qbittest.NewServer()
+	server.AddTorrent(&qbittest.Torrent{
+		Hash:     "f23daefbe8d24d3dd882b44cb0b4f762bc23b4fc",
+		Name:     "debian-12.5.0-amd64-netinst.iso",
+		Category: "movies",
+		Tags:     []string{"hdtime"},
+		Size:     659554304,
+		Progress: 0.5,
+		Trackers: []*qbittest.Tracker{{URL: "https://tracker.example.org/announce", Status: 2}},
+		WebSeeds: []string{"https://cdimage.debian.org/debian-cd/"},
+		Peers:    []*qbittest.Peer{{IP: "10.0.0.2", Port: 51413, Client: "qBittorrent 4.6.5", Progress: 1}},
+	})
+	server.AddTorrent(&qbittest.Torrent{Hash: "202382999be6a4fab395cd9c2c9d294177587904", Name: "paused", Size: 1024})
+	server.AddTorrent(&qbittest.Torrent{Hash: "fd3b4bf1937c59a8fd1a240cddc07172e0b979a2", Name: "resumed", State: "pausedUP", Size: 1024, Progress: 1})
+	server.AddTorrent(&qbittest.Torrent{Hash: "ca4523a3db9c6c3a13d7d7f3a545f97b75083032", Name: "trackers", Size: 1024})
+	server.AddTorrent(&qbittest.Torrent{Hash: "916a250d32822adca39eb2b53efadfda1a15f902", Name: "priority", Size: 1024})
+
 	var err error
 	c, err = NewClient(&Config{
-		Address:           "http://192.168.3.33:38080",
-		Username:          "admin",
-		Password:          "J0710cz5",
+		Address:           server.URL,
+		Username:          qbittest.DefaultUsername,
+		Password:          qbittest.DefaultPassword,
 		RefreshIntervals:  time.Hour,
 		ConnectionTimeout: time.Second * 3,
 		CustomHeaders: map[string]string{
-			//"Origin":     "http://192.168.3.33:8080",
-			//"Referer":    "http://192.168.3.33:8080",
 			"User-Agent": "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/125.0.0.0 Safari/537.36 Edg/125.0.0.0",
 		},
 	})
@@ -29,6 +49,22 @@ func init() {
 	}
 }
 
+// newTestClient returns a client logged in to a new fake server, the server is closed with the test
+func newTestClient(t *testing.T) (Client, *qbittest.Server) {
+	t.Helper()
+	server := qbittest.NewServer()
+	t.Cleanup(server.Close)
+	client, err := NewClient(&Config{
+		Address:  server.URL,
+		Username: qbittest.DefaultUsername,
+		Password: qbittest.DefaultPassword,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return client, server
+}
+
 func TestFormEncoder(t *testing.T) {
 	var option = LogOption{
 		Normal:      true,
@@ -52,3 +88,12 @@ func TestFormEncode(t *testing.T) {
 	fe := form.Encode()
 	t.Log(fe)
 }
+
+func TestClient_RequestContextCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := c.Application().VersionContext(ctx)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context canceled, got %v", err)
+	}
+}
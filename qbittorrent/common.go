@@ -1,6 +1,10 @@
 package qbittorrent
 
-import "github.com/gorilla/schema"
+import (
+	"sort"
+
+	"github.com/gorilla/schema"
+)
 
 const (
 	ContentTypeJSON           = "application/json"
@@ -8,3 +12,13 @@ const (
 )
 
 var encoder = schema.NewEncoder()
+
+// sortedKeys returns the keys of a map in order
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -1,14 +1,25 @@
 package qbittorrent
 
-import "time"
+import (
+	"log/slog"
+	"net/http"
+	"time"
+)
 
 type Config struct {
-	// Address qBittorrent endpoint
+	// Address qBittorrent endpoint such as http://localhost:8080 or https://host/qbittorrent/, a unix
+	// socket is addressed as unix:///path/to/socket with an optional ?host=virtual.host
 	Address string
 	// Username used to access the WebUI
 	Username string
 	// Password used to access the WebUI
 	Password string
+	// AuthMode how to authenticate, default AuthCookie login with Username and Password
+	AuthMode AuthMode
+	// AuthToken token of AuthBearer and AuthHeader
+	AuthToken string
+	// AuthHeader header name of AuthHeader, such as X-Auth-Token
+	AuthHeader string
 
 	// HTTP configuration
 
@@ -18,8 +29,28 @@ type Config struct {
 	ConnectionTimeout time.Duration
 	// ConnectionMaxIdles http client pool, default 128
 	ConnectionMaxIdles int
+	// TLS configuration of the built-in transport, nil verifies the server with the system roots
+	TLS *TLSOption
+	// HTTPClient used to send the requests instead of the built-in client, ConnectionTimeout,
+	// ConnectionMaxIdles and TLS are ignored and the Jar is replaced by the session of the client
+	HTTPClient *http.Client
+	// Middlewares wrap the transport of the client, the first middleware is the outermost
+	Middlewares []Middleware
+	// Logger logs every request at debug level with method, endpoint, status, latency and sizes,
+	// the password fields, the SID cookie and RedactHeaders are redacted, nil disables the logging
+	Logger *slog.Logger
+	// RedactHeaders additional headers redacted in the logs, such as custom auth headers
+	RedactHeaders []string
+	// SessionStore reuse the saved session instead of login in NewClient, the session is saved after every login
+	SessionStore SessionStore
 	// RefreshCookie whether to automatically refresh cookies
 	RefreshCookie bool
 	// SessionTimeout interval for refreshing cookies, default 1 hour
 	RefreshIntervals time.Duration
+	// DisableAutoReLogin do not login again and replay the request when the server responds 403
+	DisableAutoReLogin bool
+	// Retry retry policy for transient failures, nil means never retry
+	Retry *RetryPolicy
+	// OnReAuthenticate called after every automatic re-login, err is nil if the login succeeded
+	OnReAuthenticate func(err error)
 }
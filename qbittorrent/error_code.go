@@ -1,8 +1,115 @@
 package qbittorrent
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
 
 var (
 	ErrNotLogin   = errors.New("not login")
 	ErrAuthFailed = errors.New("auth failed")
+
+	// ErrBadRequest the server rejected the request parameters, usually HTTP 400
+	ErrBadRequest = errors.New("bad request")
+	// ErrForbidden the session is expired or the client is not logged in, usually HTTP 403
+	ErrForbidden = errors.New("forbidden")
+	// ErrNotFound the torrent hash or resource was not found, usually HTTP 404
+	ErrNotFound = errors.New("not found")
+	// ErrConflict the request conflicts with the server state, e.g. invalid category, usually HTTP 409
+	ErrConflict = errors.New("conflict")
+	// ErrUnsupportedMediaType the torrent file is not valid, usually HTTP 415
+	ErrUnsupportedMediaType = errors.New("unsupported media type")
+	// ErrUnsupported the feature is not supported by the WebAPI version of the server
+	ErrUnsupported = errors.New("unsupported by the server")
 )
+
+// UnsupportedError is returned when a feature requires a newer WebAPI version than the server has,
+// errors.Is(err, ErrUnsupported) reports true for it
+type UnsupportedError struct {
+	// Feature name of the unsupported feature
+	Feature string
+	// Required minimum WebAPI version of the feature
+	Required Version
+	// Actual WebAPI version of the server
+	Actual Version
+}
+
+func (e *UnsupportedError) Error() string {
+	return fmt.Sprintf("%s requires webapi %s, the server has %s", e.Feature, e.Required, e.Actual)
+}
+
+func (e *UnsupportedError) Is(target error) bool {
+	return target == ErrUnsupported
+}
+
+// APIError is returned when the qBittorrent WebUI responds with an unexpected HTTP status,
+// use errors.As to inspect it, or errors.Is with the sentinel errors above
+type APIError struct {
+	// StatusCode HTTP status code of the response
+	StatusCode int
+	// Method HTTP method of the request
+	Method string
+	// Endpoint path of the request, such as /api/v2/torrents/info
+	Endpoint string
+	// Body raw response body
+	Body []byte
+
+	message string
+}
+
+func newAPIError(message string, result *responseResult) *APIError {
+	return &APIError{
+		StatusCode: result.code,
+		Method:     result.method,
+		Endpoint:   result.endpoint,
+		Body:       result.body,
+		message:    message,
+	}
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("%s: %s %s returned %d: %s", e.message, e.Method, e.Endpoint, e.StatusCode, string(e.Body))
+}
+
+// Is reports whether the status code of the error matches the target sentinel error
+func (e *APIError) Is(target error) bool {
+	switch target {
+	case ErrBadRequest:
+		return e.StatusCode == http.StatusBadRequest
+	case ErrForbidden:
+		return e.StatusCode == http.StatusForbidden
+	case ErrNotFound:
+		return e.StatusCode == http.StatusNotFound
+	case ErrConflict:
+		return e.StatusCode == http.StatusConflict
+	case ErrUnsupportedMediaType:
+		return e.StatusCode == http.StatusUnsupportedMediaType
+	}
+	return false
+}
+
+// IsBadRequest reports whether err is caused by a 400 response
+func IsBadRequest(err error) bool {
+	return errors.Is(err, ErrBadRequest)
+}
+
+// IsForbidden reports whether err is caused by a 403 response
+func IsForbidden(err error) bool {
+	return errors.Is(err, ErrForbidden)
+}
+
+// IsNotFound reports whether err is caused by a 404 response
+func IsNotFound(err error) bool {
+	return errors.Is(err, ErrNotFound)
+}
+
+// IsConflict reports whether err is caused by a 409 response
+func IsConflict(err error) bool {
+	return errors.Is(err, ErrConflict)
+}
+
+// IsUnsupportedMediaType reports whether err is caused by a 415 response
+func IsUnsupportedMediaType(err error) bool {
+	return errors.Is(err, ErrUnsupportedMediaType)
+}
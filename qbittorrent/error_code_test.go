@@ -0,0 +1,34 @@
+package qbittorrent
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestAPIError_Is(t *testing.T) {
+	var err error = newAPIError("get torrent properties failed", &responseResult{
+		code:     http.StatusNotFound,
+		body:     []byte("Not Found"),
+		method:   http.MethodGet,
+		endpoint: "/api/v2/torrents/properties",
+	})
+	err = fmt.Errorf("wrapped: %w", err)
+
+	if !IsNotFound(err) {
+		t.Fatal("expected not found error")
+	}
+	if IsForbidden(err) || IsConflict(err) {
+		t.Fatal("unexpected status matched")
+	}
+
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatal("expected *APIError")
+	}
+	if apiErr.Endpoint != "/api/v2/torrents/properties" || apiErr.Method != http.MethodGet {
+		t.Fatalf("unexpected api error: %+v", apiErr)
+	}
+	t.Log(err)
+}
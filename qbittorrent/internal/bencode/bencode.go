@@ -0,0 +1,170 @@
+// Package bencode decodes the bencode format of .torrent files.
+//
+// Decoded values are int64, string, []any and map[string]any. Byte strings are returned as Go
+// strings, they may hold arbitrary binary data such as the pieces of a torrent.
+package bencode
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+// maxDepth maximum nesting of lists and dictionaries, protects against stack exhaustion
+const maxDepth = 256
+
+// ErrInvalid is wrapped by every decoding error
+var ErrInvalid = errors.New("invalid bencode")
+
+// Decode decodes a single bencoded value, trailing data is an error
+func Decode(data []byte) (any, error) {
+	d := &decoder{data: data}
+	value, err := d.value(0)
+	if err != nil {
+		return nil, err
+	}
+	if d.pos != len(data) {
+		return nil, d.errorf("trailing data")
+	}
+	return value, nil
+}
+
+// RawValue returns the raw bencoded value of key in the top level dictionary, such as the info
+// dictionary of a torrent whose hash is the infohash. ok is false if the key does not exist
+func RawValue(data []byte, key string) (raw []byte, ok bool, err error) {
+	d := &decoder{data: data}
+	if d.peek() != 'd' {
+		return nil, false, d.errorf("expected dictionary")
+	}
+	d.pos++
+	for d.peek() != 'e' {
+		k, err := d.string()
+		if err != nil {
+			return nil, false, err
+		}
+		start := d.pos
+		if _, err := d.value(1); err != nil {
+			return nil, false, err
+		}
+		if k == key {
+			raw, ok = data[start:d.pos], true
+		}
+	}
+	d.pos++
+	if d.pos != len(data) {
+		return nil, false, d.errorf("trailing data")
+	}
+	return raw, ok, nil
+}
+
+type decoder struct {
+	data []byte
+	pos  int
+}
+
+func (d *decoder) errorf(format string, args ...any) error {
+	return fmt.Errorf("%w: %s at offset %d", ErrInvalid, fmt.Sprintf(format, args...), d.pos)
+}
+
+// peek returns the current byte, 0 at the end of the data
+func (d *decoder) peek() byte {
+	if d.pos >= len(d.data) {
+		return 0
+	}
+	return d.data[d.pos]
+}
+
+func (d *decoder) value(depth int) (any, error) {
+	if depth > maxDepth {
+		return nil, d.errorf("nesting too deep")
+	}
+	switch c := d.peek(); {
+	case c == 'i':
+		return d.integer()
+	case c == 'l':
+		d.pos++
+		var list = []any{}
+		for d.peek() != 'e' {
+			if d.peek() == 0 {
+				return nil, d.errorf("unterminated list")
+			}
+			item, err := d.value(depth + 1)
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, item)
+		}
+		d.pos++
+		return list, nil
+	case c == 'd':
+		d.pos++
+		var dict = map[string]any{}
+		for d.peek() != 'e' {
+			if d.peek() == 0 {
+				return nil, d.errorf("unterminated dictionary")
+			}
+			key, err := d.string()
+			if err != nil {
+				return nil, err
+			}
+			item, err := d.value(depth + 1)
+			if err != nil {
+				return nil, err
+			}
+			dict[key] = item
+		}
+		d.pos++
+		return dict, nil
+	case c >= '0' && c <= '9':
+		return d.string()
+	case c == 0:
+		return nil, d.errorf("unexpected end of data")
+	default:
+		return nil, d.errorf("unexpected character %q", c)
+	}
+}
+
+// integer decodes i<number>e, leading zeros and negative zero are rejected
+func (d *decoder) integer() (int64, error) {
+	d.pos++
+	start := d.pos
+	for d.peek() != 'e' {
+		if d.peek() == 0 {
+			return 0, d.errorf("unterminated integer")
+		}
+		d.pos++
+	}
+	text := string(d.data[start:d.pos])
+	d.pos++
+	if text == "" || text == "-0" || (len(text) > 1 && text[0] == '0') || (len(text) > 2 && text[:2] == "-0") {
+		return 0, d.errorf("invalid integer %q", text)
+	}
+	n, err := strconv.ParseInt(text, 10, 64)
+	if err != nil {
+		return 0, d.errorf("invalid integer %q", text)
+	}
+	return n, nil
+}
+
+// string decodes <length>:<bytes>
+func (d *decoder) string() (string, error) {
+	start := d.pos
+	for d.peek() >= '0' && d.peek() <= '9' {
+		d.pos++
+	}
+	if d.peek() != ':' || d.pos == start {
+		return "", d.errorf("expected string")
+	}
+	text := string(d.data[start:d.pos])
+	if len(text) > 1 && text[0] == '0' {
+		return "", d.errorf("invalid string length %q", text)
+	}
+	length, err := strconv.Atoi(text)
+	if err != nil || length > len(d.data)-d.pos-1 {
+		return "", d.errorf("string length %s out of range", text)
+	}
+	d.pos++
+	value := string(d.data[d.pos : d.pos+length])
+	d.pos += length
+	return value, nil
+}
@@ -0,0 +1,55 @@
+package bencode
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDecode(t *testing.T) {
+	for input, expected := range map[string]any{
+		"i42e":                       int64(42),
+		"i-7e":                       int64(-7),
+		"i0e":                        int64(0),
+		"4:spam":                     "spam",
+		"0:":                         "",
+		"le":                         []any{},
+		"l4:spami1ee":                []any{"spam", int64(1)},
+		"de":                         map[string]any{},
+		"d3:cow3:moo4:spaml1:a1:bee": map[string]any{"cow": "moo", "spam": []any{"a", "b"}},
+	} {
+		value, err := Decode([]byte(input))
+		if err != nil {
+			t.Fatalf("%s: %v", input, err)
+		}
+		if !reflect.DeepEqual(value, expected) {
+			t.Fatalf("%s: expected %#v, got %#v", input, expected, value)
+		}
+	}
+
+	for _, input := range []string{"", "i42", "ie", "i-0e", "i03e", "i1.5e", "5:spam", "03:abc", "l4:spam",
+		"d3:cowe", "di1ei2ee", "x", "i1ei2e", strings.Repeat("l", maxDepth+2) + strings.Repeat("e", maxDepth+2)} {
+		if _, err := Decode([]byte(input)); !errors.Is(err, ErrInvalid) {
+			t.Fatalf("%q: expected ErrInvalid, got %v", input, err)
+		}
+	}
+}
+
+func TestRawValue(t *testing.T) {
+	data := []byte("d8:announce3:url4:infod4:name1:ae5:otheri1ee")
+	raw, ok, err := RawValue(data, "info")
+	if err != nil || !ok {
+		t.Fatalf("expected the info value, got %v %v", ok, err)
+	}
+	if string(raw) != "d4:name1:ae" {
+		t.Fatalf("unexpected raw value: %s", raw)
+	}
+
+	if _, ok, err := RawValue(data, "missing"); err != nil || ok {
+		t.Fatalf("expected no value, got %v %v", ok, err)
+	}
+	if _, _, err := RawValue([]byte("l4:infoe"), "info"); !errors.Is(err, ErrInvalid) {
+		t.Fatalf("expected ErrInvalid, got %v", err)
+	}
+}
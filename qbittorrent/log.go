@@ -1,8 +1,7 @@
 package qbittorrent
 
 import (
-	"errors"
-	"fmt"
+	"context"
 	"net/url"
 	"strconv"
 	"strings"
@@ -31,19 +30,27 @@ type LogEntry struct {
 type Log interface {
 	// GetLog get log
 	GetLog(option *LogOption) ([]*LogEntry, error)
+	// GetLogContext is the context-aware version of GetLog
+	GetLogContext(ctx context.Context, option *LogOption) ([]*LogEntry, error)
 	// GetPeerLog get peer log
 	GetPeerLog(lastKnownId int) ([]*LogEntry, error)
+	// GetPeerLogContext is the context-aware version of GetPeerLog
+	GetPeerLogContext(ctx context.Context, lastKnownId int) ([]*LogEntry, error)
 }
 
 func (c *client) GetLog(option *LogOption) ([]*LogEntry, error) {
+	return c.GetLogContext(context.Background(), option)
+}
+
+func (c *client) GetLogContext(ctx context.Context, option *LogOption) ([]*LogEntry, error) {
 	var form = url.Values{}
 	err := encoder.Encode(option, form)
 	if err != nil {
 		return nil, err
 	}
-	apiUrl := fmt.Sprintf("%s/api/v2/log/main?%s", c.config.Address, form.Encode())
+	apiUrl := c.apiURL("log/main", form)
 
-	result, err := c.doRequest(&requestData{
+	result, err := c.doRequest(ctx, &requestData{
 		url:  apiUrl,
 		body: strings.NewReader(form.Encode()),
 	})
@@ -52,7 +59,7 @@ func (c *client) GetLog(option *LogOption) ([]*LogEntry, error) {
 	}
 
 	if result.code != 200 {
-		return nil, errors.New("get log failed: " + string(result.body))
+		return nil, newAPIError("get log failed", result)
 	}
 
 	var logs []*LogEntry
@@ -64,11 +71,15 @@ func (c *client) GetLog(option *LogOption) ([]*LogEntry, error) {
 }
 
 func (c *client) GetPeerLog(lastKnownId int) ([]*LogEntry, error) {
-	apiUrl := fmt.Sprintf("%s/api/v2/log/peers", c.config.Address)
+	return c.GetPeerLogContext(context.Background(), lastKnownId)
+}
+
+func (c *client) GetPeerLogContext(ctx context.Context, lastKnownId int) ([]*LogEntry, error) {
 	var form = url.Values{}
 	form.Add("last_known_id", strconv.Itoa(lastKnownId))
+	apiUrl := c.apiURL("log/peers", form)
 
-	result, err := c.doRequest(&requestData{
+	result, err := c.doRequest(ctx, &requestData{
 		url:  apiUrl,
 		body: strings.NewReader(form.Encode()),
 	})
@@ -77,7 +88,7 @@ func (c *client) GetPeerLog(lastKnownId int) ([]*LogEntry, error) {
 	}
 
 	if result.code != 200 {
-		return nil, errors.New("get peer log failed: " + string(result.body))
+		return nil, newAPIError("get peer log failed", result)
 	}
 
 	var logs []*LogEntry
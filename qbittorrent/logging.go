@@ -0,0 +1,128 @@
+package qbittorrent
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+)
+
+// redacted replaces the value of the sensitive headers, cookies and form fields in the logs
+const redacted = "[REDACTED]"
+
+// sensitive headers, cookies and form fields that are always redacted, Config.RedactHeaders adds more headers
+var (
+	redactedHeaders = []string{"Authorization", "Proxy-Authorization"}
+	redactedCookies = []string{"SID"}
+	redactedFields  = []string{"password"}
+)
+
+// logRequest logs a finished request to Config.Logger, err is the transport error if any
+func (c *client) logRequest(ctx context.Context, request *http.Request, body []byte, resp *http.Response,
+	respBody []byte, latency time.Duration, err error) {
+	var logger = c.config.Logger
+	var level = slog.LevelDebug
+	if err != nil {
+		level = slog.LevelWarn
+	}
+	if logger == nil || !logger.Enabled(ctx, level) {
+		return
+	}
+
+	var attrs = []slog.Attr{
+		slog.String("method", request.Method),
+		slog.String("endpoint", request.URL.Path),
+	}
+	if request.URL.RawQuery != "" {
+		attrs = append(attrs, slog.String("query", redactForm(request.URL.RawQuery)))
+	}
+	if strings.HasPrefix(request.Header.Get("Content-Type"), ContentTypeFormUrlEncoded) && len(body) > 0 {
+		attrs = append(attrs, slog.String("form", redactForm(string(body))))
+	}
+	attrs = append(attrs,
+		slog.Duration("latency", latency),
+		slog.Int64("request_size", request.ContentLength),
+		slog.Any("request_headers", c.redactHeaders(request.Header)),
+	)
+	if err != nil {
+		logger.LogAttrs(ctx, level, "qbittorrent request failed", append(attrs, slog.String("error", err.Error()))...)
+		return
+	}
+	attrs = append(attrs,
+		slog.Int("status", resp.StatusCode),
+		slog.Int("response_size", len(respBody)),
+		slog.Any("response_headers", c.redactHeaders(resp.Header)),
+	)
+	logger.LogAttrs(ctx, level, "qbittorrent request", attrs...)
+}
+
+// redactHeaders returns a copy of the headers with the credentials replaced
+func (c *client) redactHeaders(header http.Header) map[string]string {
+	var headers = make(map[string]string, len(header))
+	for key, values := range header {
+		var value = strings.Join(values, ", ")
+		switch {
+		case key == "Cookie":
+			value = redactCookies(values, "; ")
+		case key == "Set-Cookie":
+			value = redactCookies(values, ", ")
+		case c.isRedactedHeader(key):
+			value = redacted
+		}
+		headers[key] = value
+	}
+	return headers
+}
+
+func (c *client) isRedactedHeader(key string) bool {
+	var match = func(name string) bool {
+		return strings.EqualFold(name, key)
+	}
+	return slices.ContainsFunc(redactedHeaders, match) || slices.ContainsFunc(c.config.RedactHeaders, match) ||
+		(c.config.AuthHeader != "" && match(c.config.AuthHeader))
+}
+
+// redactCookies replace the value of the session cookies in Cookie or Set-Cookie header values
+func redactCookies(values []string, sep string) string {
+	var result = make([]string, 0, len(values))
+	for _, value := range values {
+		var pairs = strings.Split(value, ";")
+		for i, pair := range pairs {
+			var trimmed = strings.TrimSpace(pair)
+			name, _, ok := strings.Cut(trimmed, "=")
+			if ok && slices.Contains(redactedCookies, name) {
+				pairs[i] = strings.Replace(pair, trimmed, name+"="+redacted, 1)
+			}
+		}
+		result = append(result, strings.Join(pairs, ";"))
+	}
+	return strings.Join(result, sep)
+}
+
+// redactForm returns the url encoded form with the password fields replaced, the json field of
+// setPreferences is replaced if it contains a password, the whole form is replaced if it cannot be parsed
+func redactForm(raw string) string {
+	form, err := url.ParseQuery(raw)
+	if err != nil {
+		return redacted
+	}
+	for _, field := range redactedFields {
+		if form.Has(field) {
+			form.Set(field, redacted)
+		}
+	}
+	if strings.Contains(form.Get("json"), "password") {
+		form.Set("json", redacted)
+	}
+	// the values are not escaped again to keep the logs readable
+	var pairs = make([]string, 0, len(form))
+	for _, key := range sortedKeys(form) {
+		for _, value := range form[key] {
+			pairs = append(pairs, key+"="+value)
+		}
+	}
+	return strings.Join(pairs, "&")
+}
@@ -0,0 +1,125 @@
+package qbittorrent
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/xuthus5/qbittorrent-client-go/qbittorrent/qbittest"
+)
+
+func TestClient_Logger(t *testing.T) {
+	server := qbittest.NewServer()
+	defer server.Close()
+
+	var output bytes.Buffer
+	var sessions []string
+	recordSession := func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			resp, err := next.RoundTrip(req)
+			if err == nil {
+				for _, cookie := range resp.Cookies() {
+					sessions = append(sessions, cookie.Value)
+				}
+			}
+			return resp, err
+		})
+	}
+	client, err := NewClient(&Config{
+		Address:       server.URL,
+		Username:      qbittest.DefaultUsername,
+		Password:      qbittest.DefaultPassword,
+		CustomHeaders: map[string]string{"X-Api-Key": "secret-key", "Authorization": "Bearer secret-token"},
+		RedactHeaders: []string{"x-api-key"},
+		Middlewares:   []Middleware{recordSession},
+		Logger:        slog.New(slog.NewJSONHandler(&output, &slog.HandlerOptions{Level: slog.LevelDebug})),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.Torrent().GetTorrents(&TorrentOption{Filter: FilterAll}); err != nil {
+		t.Fatal(err)
+	}
+
+	logs := output.String()
+	for _, secret := range []string{qbittest.DefaultPassword, "secret-key", "secret-token"} {
+		if strings.Contains(logs, secret) {
+			t.Fatalf("expected %q to be redacted: %s", secret, logs)
+		}
+	}
+	if len(sessions) == 0 {
+		t.Fatal("expected a session cookie")
+	}
+	for _, session := range sessions {
+		if strings.Contains(logs, session) {
+			t.Fatalf("expected the session cookie to be redacted: %s", logs)
+		}
+	}
+
+	var entries []map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(logs), "\n") {
+		var entry map[string]any
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			t.Fatal(err)
+		}
+		entries = append(entries, entry)
+	}
+	login, last := entries[0], entries[len(entries)-1]
+	if login["endpoint"] != "/api/v2/auth/login" || login["form"] != "password=[REDACTED]&username="+qbittest.DefaultUsername {
+		t.Fatalf("unexpected login entry: %v", login)
+	}
+	if last["method"] != http.MethodGet || last["endpoint"] != "/api/v2/torrents/info" || last["status"] != float64(200) {
+		t.Fatalf("unexpected entry: %v", last)
+	}
+	for _, key := range []string{"latency", "request_size", "response_size", "request_headers", "response_headers"} {
+		if _, ok := last[key]; !ok {
+			t.Fatalf("expected %s in the entry: %v", key, last)
+		}
+	}
+}
+
+func TestClient_LoggerPreferences(t *testing.T) {
+	server := qbittest.NewServer()
+	defer server.Close()
+	var output bytes.Buffer
+	client, err := NewClient(&Config{
+		Address:  server.URL,
+		Username: qbittest.DefaultUsername,
+		Password: qbittest.DefaultPassword,
+		Logger:   slog.New(slog.NewJSONHandler(&output, &slog.HandlerOptions{Level: slog.LevelDebug})),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the form separators and escapes in the json must be encoded
+	const password = "a&leaked+part%25"
+	if err := client.Application().SetPreferences(&Preferences{ProxyPassword: password}); err != nil {
+		t.Fatal(err)
+	}
+	if value := server.Preferences()["proxy_password"]; value != password {
+		t.Fatalf("unexpected password on the server: %v", value)
+	}
+	if strings.Contains(output.String(), "leaked") {
+		t.Fatalf("expected the password to be redacted: %s", output.String())
+	}
+}
+
+func TestRedact(t *testing.T) {
+	cookies := redactCookies([]string{"SID=abc; HttpOnly; path=/"}, ", ")
+	if cookies != "SID=[REDACTED]; HttpOnly; path=/" {
+		t.Fatalf("unexpected cookies: %s", cookies)
+	}
+	cookies = redactCookies([]string{"theme=dark; SID=abc"}, "; ")
+	if cookies != "theme=dark; SID=[REDACTED]" {
+		t.Fatalf("unexpected cookies: %s", cookies)
+	}
+
+	form := redactForm("json=%7B%22web_ui_password%22%3A%22secret%22%7D&hashes=all")
+	if form != "hashes=all&json=[REDACTED]" {
+		t.Fatalf("unexpected form: %s", form)
+	}
+}
@@ -0,0 +1,226 @@
+package qbittorrent
+
+import (
+	"encoding/base32"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// magnet link prefixes of the exact topic
+const (
+	magnetBTIH = "urn:btih:"
+	// magnetBTMH is a sha2-256 multihash, 0x12 is the hash function and 0x20 the length
+	magnetBTMH = "urn:btmh:1220"
+)
+
+// maxMagnetFiles limits the file indexes of the so parameter, the ranges are expanded
+const maxMagnetFiles = 1 << 16
+
+// Magnet is a parsed magnet link, String builds the link again
+type Magnet struct {
+	// InfoHashV1 lower case hex v1 infohash, base32 hashes are converted, empty if the link has no btih
+	InfoHashV1 string
+	// InfoHashV2 lower case hex v2 infohash, empty if the link has no btmh
+	InfoHashV2 string
+	// Name display name, dn
+	Name string
+	// Trackers tracker urls, tr
+	Trackers []string
+	// WebSeeds web seed urls, ws
+	WebSeeds []string
+	// Length total size in bytes, xl, 0 if unknown
+	Length int64
+	// SelectOnly indexes of the files to download, so, empty means all files
+	SelectOnly []int
+	// Extra parameters not handled by Magnet, such as x.pe peer addresses, they are kept by String
+	Extra url.Values
+}
+
+// ParseMagnet parse a magnet link with a btih and/or btmh exact topic
+func ParseMagnet(uri string) (*Magnet, error) {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return nil, fmt.Errorf("invalid magnet link: %w", err)
+	}
+	if u.Scheme != "magnet" {
+		return nil, errors.New("invalid magnet link: scheme must be magnet")
+	}
+	query, err := url.ParseQuery(u.RawQuery)
+	if err != nil {
+		return nil, fmt.Errorf("invalid magnet link: %w", err)
+	}
+
+	var m = &Magnet{
+		Name:     query.Get("dn"),
+		Trackers: query["tr"],
+		WebSeeds: query["ws"],
+	}
+	for _, xt := range query["xt"] {
+		switch {
+		case strings.HasPrefix(strings.ToLower(xt), magnetBTIH):
+			if m.InfoHashV1, err = normalizeInfoHashV1(xt[len(magnetBTIH):]); err != nil {
+				return nil, err
+			}
+		case strings.HasPrefix(strings.ToLower(xt), magnetBTMH):
+			hash := strings.ToLower(xt[len(magnetBTMH):])
+			if _, err := hex.DecodeString(hash); err != nil || len(hash) != 64 {
+				return nil, fmt.Errorf("invalid magnet link: invalid btmh %q", xt)
+			}
+			m.InfoHashV2 = hash
+		}
+	}
+	if m.InfoHashV1 == "" && m.InfoHashV2 == "" {
+		return nil, errors.New("invalid magnet link: missing btih or btmh exact topic")
+	}
+	if xl := query.Get("xl"); xl != "" {
+		if m.Length, err = strconv.ParseInt(xl, 10, 64); err != nil || m.Length < 0 {
+			return nil, fmt.Errorf("invalid magnet link: invalid xl %q", xl)
+		}
+	}
+	if so := query.Get("so"); so != "" {
+		if m.SelectOnly, err = parseSelectOnly(so); err != nil {
+			return nil, err
+		}
+	}
+	for key, values := range query {
+		switch key {
+		case "xt", "dn", "tr", "ws", "xl", "so":
+		default:
+			if m.Extra == nil {
+				m.Extra = url.Values{}
+			}
+			m.Extra[key] = values
+		}
+	}
+	return m, nil
+}
+
+// normalizeInfoHashV1 convert a 40 characters hex or 32 characters base32 btih to lower case hex
+func normalizeInfoHashV1(hash string) (string, error) {
+	switch len(hash) {
+	case 40:
+		if _, err := hex.DecodeString(hash); err == nil {
+			return strings.ToLower(hash), nil
+		}
+	case 32:
+		if data, err := base32.StdEncoding.DecodeString(strings.ToUpper(hash)); err == nil {
+			return hex.EncodeToString(data), nil
+		}
+	}
+	return "", fmt.Errorf("invalid magnet link: invalid btih %q", hash)
+}
+
+// parseSelectOnly parse the so parameter such as "0,2,4-6", indexes and the total number of
+// indexes are limited to maxMagnetFiles
+func parseSelectOnly(so string) ([]int, error) {
+	var indexes []int
+	for _, part := range strings.Split(so, ",") {
+		first, last, isRange := strings.Cut(part, "-")
+		start, err := strconv.Atoi(first)
+		end := start
+		if err == nil && isRange {
+			end, err = strconv.Atoi(last)
+		}
+		if err != nil || start < 0 || end < start || end >= maxMagnetFiles || len(indexes)+end-start >= maxMagnetFiles {
+			return nil, fmt.Errorf("invalid magnet link: invalid so %q", so)
+		}
+		for i := start; i <= end; i++ {
+			indexes = append(indexes, i)
+		}
+	}
+	return indexes, nil
+}
+
+// Hash returns the hash qBittorrent identifies the torrent with, the same as TorrentInfo.Hash,
+// or "" if the link has no hash
+func (m *Magnet) Hash() string {
+	if m.InfoHashV1 != "" {
+		return m.InfoHashV1
+	}
+	if len(m.InfoHashV2) >= 40 {
+		return m.InfoHashV2[:40]
+	}
+	return ""
+}
+
+// AddTrackers append the trackers that are not in the link yet
+func (m *Magnet) AddTrackers(urls ...string) {
+	for _, u := range urls {
+		if !slices.Contains(m.Trackers, u) {
+			m.Trackers = append(m.Trackers, u)
+		}
+	}
+}
+
+// RemoveTrackers remove the trackers from the link
+func (m *Magnet) RemoveTrackers(urls ...string) {
+	m.Trackers = slices.DeleteFunc(m.Trackers, func(u string) bool {
+		return slices.Contains(urls, u)
+	})
+}
+
+// StripTrackers remove all trackers, the torrent is then found by DHT and PeX only
+func (m *Magnet) StripTrackers() {
+	m.Trackers = nil
+}
+
+// String build the magnet link, the exact topics come first and the values are escaped
+func (m *Magnet) String() string {
+	var params []string
+	if m.InfoHashV1 != "" {
+		params = append(params, "xt="+magnetBTIH+m.InfoHashV1)
+	}
+	if m.InfoHashV2 != "" {
+		params = append(params, "xt="+magnetBTMH+m.InfoHashV2)
+	}
+	if m.Name != "" {
+		params = append(params, "dn="+url.QueryEscape(m.Name))
+	}
+	if m.Length > 0 {
+		params = append(params, "xl="+strconv.FormatInt(m.Length, 10))
+	}
+	for _, tracker := range m.Trackers {
+		params = append(params, "tr="+url.QueryEscape(tracker))
+	}
+	for _, seed := range m.WebSeeds {
+		params = append(params, "ws="+url.QueryEscape(seed))
+	}
+	if len(m.SelectOnly) != 0 {
+		params = append(params, "so="+formatSelectOnly(m.SelectOnly))
+	}
+	if len(m.Extra) != 0 {
+		params = append(params, m.Extra.Encode())
+	}
+	return "magnet:?" + strings.Join(params, "&")
+}
+
+// formatSelectOnly format the indexes with ranges for consecutive indexes
+func formatSelectOnly(indexes []int) string {
+	var sorted = slices.Clone(indexes)
+	slices.Sort(sorted)
+	sorted = slices.Compact(sorted)
+	var parts []string
+	for i := 0; i < len(sorted); {
+		j := i
+		for j+1 < len(sorted) && sorted[j+1] == sorted[j]+1 {
+			j++
+		}
+		if j > i {
+			parts = append(parts, fmt.Sprintf("%d-%d", sorted[i], sorted[j]))
+		} else {
+			parts = append(parts, strconv.Itoa(sorted[i]))
+		}
+		i = j + 1
+	}
+	return strings.Join(parts, ",")
+}
+
+// Magnet parse the magnet link of the torrent
+func (t *TorrentInfo) Magnet() (*Magnet, error) {
+	return ParseMagnet(t.MagnetURI)
+}
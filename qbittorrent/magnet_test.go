@@ -0,0 +1,133 @@
+package qbittorrent
+
+import (
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestParseMagnet(t *testing.T) {
+	const uri = "magnet:?xt=urn:btih:F23DAEFBE8D24D3DD882B44CB0B4F762BC23B4FC&dn=debian-12.5.0-amd64-netinst.iso" +
+		"&xl=659554304&tr=https%3A%2F%2Ftracker.example.org%2Fannounce&tr=udp%3A%2F%2Ftracker.example.net%3A6969" +
+		"&ws=https%3A%2F%2Fcdimage.debian.org%2F&so=0,2,4-6&x.pe=10.0.0.2:51413"
+	m, err := ParseMagnet(uri)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.InfoHashV1 != "f23daefbe8d24d3dd882b44cb0b4f762bc23b4fc" || m.Hash() != m.InfoHashV1 || m.InfoHashV2 != "" {
+		t.Fatalf("unexpected hashes: %s %s", m.InfoHashV1, m.InfoHashV2)
+	}
+	if m.Name != "debian-12.5.0-amd64-netinst.iso" || m.Length != 659554304 {
+		t.Fatalf("unexpected magnet: %+v", m)
+	}
+	if !slices.Equal(m.Trackers, []string{"https://tracker.example.org/announce", "udp://tracker.example.net:6969"}) {
+		t.Fatalf("unexpected trackers: %v", m.Trackers)
+	}
+	if !slices.Equal(m.WebSeeds, []string{"https://cdimage.debian.org/"}) || !slices.Equal(m.SelectOnly, []int{0, 2, 4, 5, 6}) {
+		t.Fatalf("unexpected magnet: %+v", m)
+	}
+	if m.Extra.Get("x.pe") != "10.0.0.2:51413" {
+		t.Fatalf("unexpected extra parameters: %v", m.Extra)
+	}
+
+	again, err := ParseMagnet(m.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(m, again) {
+		t.Fatalf("expected the same magnet after String, got %+v", again)
+	}
+	if !strings.HasPrefix(m.String(), "magnet:?xt=urn:btih:f23daefbe8d24d3dd882b44cb0b4f762bc23b4fc&dn=") {
+		t.Fatalf("unexpected link: %s", m)
+	}
+
+	// base32 btih
+	m, err = ParseMagnet("magnet:?xt=urn:btih:6PW274FI2SJT3WEC6RGLBMHXMK6CHNH4")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.Hash() != "f3edaff0a8d4933dd882f44cb0b0f762bc23b4fc" {
+		t.Fatalf("unexpected hash: %s", m.Hash())
+	}
+
+	// hybrid and v2-only
+	const v2 = "3dfd6a88e5e1a59f7cfaf6e1ad1f4d2d1e56b0b9c3a3fb1f0d4d3d33e6b1b2a1"
+	m, err = ParseMagnet("magnet:?xt=urn:btih:f23daefbe8d24d3dd882b44cb0b4f762bc23b4fc&xt=urn:btmh:1220" + v2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.InfoHashV2 != v2 || m.Hash() != "f23daefbe8d24d3dd882b44cb0b4f762bc23b4fc" {
+		t.Fatalf("unexpected hashes: %s %s", m.InfoHashV1, m.InfoHashV2)
+	}
+	m, err = ParseMagnet("magnet:?xt=urn:btmh:1220" + v2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.Hash() != v2[:40] {
+		t.Fatalf("unexpected hash: %s", m.Hash())
+	}
+
+	for _, uri := range []string{"https://example.org", "magnet:?dn=name", "magnet:?xt=urn:btih:abc",
+		"magnet:?xt=urn:btmh:1220abc", "magnet:?xt=urn:btih:f23daefbe8d24d3dd882b44cb0b4f762bc23b4fc&xl=big",
+		"magnet:?xt=urn:btih:f23daefbe8d24d3dd882b44cb0b4f762bc23b4fc&so=3-1",
+		"magnet:?xt=urn:btih:f23daefbe8d24d3dd882b44cb0b4f762bc23b4fc&so=0-50000000",
+		"magnet:?xt=urn:btih:f23daefbe8d24d3dd882b44cb0b4f762bc23b4fc&so=0-65535,0"} {
+		if _, err := ParseMagnet(uri); err == nil {
+			t.Fatalf("%s: expected an error", uri)
+		}
+	}
+	if hash := (&Magnet{}).Hash(); hash != "" {
+		t.Fatalf("expected no hash, got %s", hash)
+	}
+}
+
+func TestMagnet_Trackers(t *testing.T) {
+	m := &Magnet{InfoHashV1: "f23daefbe8d24d3dd882b44cb0b4f762bc23b4fc", Trackers: []string{"udp://a:80"}}
+	m.AddTrackers("udp://a:80", "udp://b:80", "udp://c:80")
+	m.RemoveTrackers("udp://b:80")
+	if !slices.Equal(m.Trackers, []string{"udp://a:80", "udp://c:80"}) {
+		t.Fatalf("unexpected trackers: %v", m.Trackers)
+	}
+	if m.String() != "magnet:?xt=urn:btih:f23daefbe8d24d3dd882b44cb0b4f762bc23b4fc&tr=udp%3A%2F%2Fa%3A80&tr=udp%3A%2F%2Fc%3A80" {
+		t.Fatalf("unexpected link: %s", m)
+	}
+	m.StripTrackers()
+	if m.String() != "magnet:?xt=urn:btih:f23daefbe8d24d3dd882b44cb0b4f762bc23b4fc" {
+		t.Fatalf("unexpected link: %s", m)
+	}
+}
+
+func TestClient_AddNewTorrentMagnet(t *testing.T) {
+	client, server := newTestClient(t)
+	m, err := ParseMagnet("magnet:?xt=urn:btih:6PW274FI2SJT3WEC6RGLBMHXMK6CHNH4&dn=magnet")
+	if err != nil {
+		t.Fatal(err)
+	}
+	m.AddTrackers("https://tracker.example.org/announce")
+	hashes, err := client.Torrent().AddNewTorrent(&TorrentAddOption{URLs: []string{m.String()}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(hashes, []string{m.Hash()}) {
+		t.Fatalf("unexpected hashes: %v", hashes)
+	}
+	if _, ok := server.Torrent(m.Hash()); !ok {
+		t.Fatalf("expected torrent %s to be added", m.Hash())
+	}
+
+	torrents, err := client.Torrent().GetTorrents(&TorrentOption{Hashes: hashes})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(torrents) != 1 {
+		t.Fatalf("expected 1 torrent, got %d", len(torrents))
+	}
+	added, err := torrents[0].Magnet()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if added.Hash() != torrents[0].Hash {
+		t.Fatalf("expected the magnet hash to match the torrent hash, got %s", added.Hash())
+	}
+}
@@ -0,0 +1,245 @@
+package qbittorrent
+
+import (
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/xuthus5/qbittorrent-client-go/qbittorrent/internal/bencode"
+)
+
+// Metainfo is a parsed .torrent file, v1, v2 and hybrid torrents are supported
+type Metainfo struct {
+	// InfoHashV1 hex sha1 of the info dictionary, empty for v2-only torrents
+	InfoHashV1 string
+	// InfoHashV2 hex sha256 of the info dictionary, empty for v1-only torrents
+	InfoHashV2 string
+	// Name suggested name of the file or root folder
+	Name string
+	// PieceLength number of bytes per piece
+	PieceLength int64
+	// Private the torrent is private, DHT and PeX are disabled
+	Private bool
+	// Files files of the torrent, a single-file torrent has one file named Name, padding files are excluded
+	Files []*MetainfoFile
+	// Length total length of the files
+	Length int64
+	// Trackers announce urls grouped by tier
+	Trackers [][]string
+	// WebSeeds web seed urls
+	WebSeeds []string
+	// Comment comment of the creator
+	Comment string
+	// CreatedBy program that created the torrent
+	CreatedBy string
+}
+
+// MetainfoFile is a file of a Metainfo
+type MetainfoFile struct {
+	// Path relative path of the file, separated by "/", including the root folder of multi-file torrents
+	Path string
+	// Length file size in bytes
+	Length int64
+}
+
+// IsV1 reports whether the torrent has a v1 info dictionary
+func (m *Metainfo) IsV1() bool {
+	return m.InfoHashV1 != ""
+}
+
+// IsV2 reports whether the torrent has a v2 info dictionary
+func (m *Metainfo) IsV2() bool {
+	return m.InfoHashV2 != ""
+}
+
+// IsHybrid reports whether the torrent is both v1 and v2
+func (m *Metainfo) IsHybrid() bool {
+	return m.IsV1() && m.IsV2()
+}
+
+// Hash returns the hash qBittorrent identifies the torrent with, the v1 infohash or the v2 infohash
+// truncated to 20 bytes for v2-only torrents, "" if the metainfo has no hash
+func (m *Metainfo) Hash() string {
+	if m.IsV1() {
+		return m.InfoHashV1
+	}
+	if len(m.InfoHashV2) >= 40 {
+		return m.InfoHashV2[:40]
+	}
+	return ""
+}
+
+// ParseMetainfo parse the content of a .torrent file and compute its infohashes
+func ParseMetainfo(data []byte) (*Metainfo, error) {
+	decoded, err := bencode.Decode(data)
+	if err != nil {
+		return nil, err
+	}
+	root, ok := decoded.(map[string]any)
+	if !ok {
+		return nil, errors.New("invalid torrent: not a dictionary")
+	}
+	rawInfo, ok, err := bencode.RawValue(data, "info")
+	if err != nil {
+		return nil, err
+	}
+	info, isDict := root["info"].(map[string]any)
+	if !ok || !isDict {
+		return nil, errors.New("invalid torrent: missing info dictionary")
+	}
+
+	var m = &Metainfo{
+		Name:        bencodeString(info, "name"),
+		PieceLength: bencodeInt(info, "piece length"),
+		Private:     bencodeInt(info, "private") == 1,
+		Comment:     bencodeString(root, "comment"),
+		CreatedBy:   bencodeString(root, "created by"),
+	}
+	if m.Name == "" {
+		return nil, errors.New("invalid torrent: missing name")
+	}
+	if m.PieceLength <= 0 {
+		return nil, errors.New("invalid torrent: invalid piece length")
+	}
+
+	var v1 = info["pieces"] != nil
+	var v2 = bencodeInt(info, "meta version") == 2
+	if !v1 && !v2 {
+		return nil, errors.New("invalid torrent: neither v1 pieces nor v2 meta version")
+	}
+	if v1 {
+		if pieces, ok := info["pieces"].(string); !ok || len(pieces)%sha1.Size != 0 {
+			return nil, errors.New("invalid torrent: invalid pieces")
+		}
+		sum := sha1.Sum(rawInfo)
+		m.InfoHashV1 = hex.EncodeToString(sum[:])
+		if m.Files, err = parseFilesV1(m.Name, info); err != nil {
+			return nil, err
+		}
+	}
+	if v2 {
+		tree, ok := info["file tree"].(map[string]any)
+		if !ok {
+			return nil, errors.New("invalid torrent: missing file tree")
+		}
+		sum := sha256.Sum256(rawInfo)
+		m.InfoHashV2 = hex.EncodeToString(sum[:])
+		if !v1 {
+			if m.Files, err = parseFileTree(tree, ""); err != nil {
+				return nil, err
+			}
+			// a single-file v2 torrent has the file at the top of the tree
+			if len(m.Files) > 1 || (len(m.Files) == 1 && m.Files[0].Path != m.Name) {
+				for _, file := range m.Files {
+					file.Path = path.Join(m.Name, file.Path)
+				}
+			}
+		}
+	}
+	for _, file := range m.Files {
+		m.Length += file.Length
+	}
+
+	m.Trackers = parseTrackers(root)
+	switch seeds := root["url-list"].(type) {
+	case string:
+		m.WebSeeds = []string{seeds}
+	case []any:
+		m.WebSeeds = bencodeStrings(seeds)
+	}
+	return m, nil
+}
+
+func parseFilesV1(name string, info map[string]any) ([]*MetainfoFile, error) {
+	if _, ok := info["length"]; ok {
+		return []*MetainfoFile{{Path: name, Length: bencodeInt(info, "length")}}, nil
+	}
+	files, ok := info["files"].([]any)
+	if !ok {
+		return nil, errors.New("invalid torrent: missing length or files")
+	}
+	var result = make([]*MetainfoFile, 0, len(files))
+	for _, item := range files {
+		file, ok := item.(map[string]any)
+		if !ok {
+			return nil, errors.New("invalid torrent: invalid file")
+		}
+		// padding files of hybrid torrents
+		if strings.Contains(bencodeString(file, "attr"), "p") {
+			continue
+		}
+		parts, _ := file["path"].([]any)
+		if len(parts) == 0 {
+			return nil, errors.New("invalid torrent: invalid file path")
+		}
+		result = append(result, &MetainfoFile{
+			Path:   path.Join(append([]string{name}, bencodeStrings(parts)...)...),
+			Length: bencodeInt(file, "length"),
+		})
+	}
+	return result, nil
+}
+
+// parseFileTree walks the v2 file tree, a file is a dictionary with an empty key holding its length
+func parseFileTree(tree map[string]any, prefix string) ([]*MetainfoFile, error) {
+	var result []*MetainfoFile
+	for _, name := range sortedKeys(tree) {
+		node, ok := tree[name].(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("invalid torrent: invalid file tree node %q", name)
+		}
+		if name == "" {
+			leaf, _ := node["length"].(int64)
+			return []*MetainfoFile{{Path: prefix, Length: leaf}}, nil
+		}
+		files, err := parseFileTree(node, path.Join(prefix, name))
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, files...)
+	}
+	return result, nil
+}
+
+// parseTrackers returns the announce-list tiers, or the announce url as the single tier
+func parseTrackers(root map[string]any) [][]string {
+	var tiers [][]string
+	if list, ok := root["announce-list"].([]any); ok {
+		for _, item := range list {
+			tier, _ := item.([]any)
+			if urls := bencodeStrings(tier); len(urls) != 0 {
+				tiers = append(tiers, urls)
+			}
+		}
+	}
+	if announce := bencodeString(root, "announce"); announce != "" &&
+		!slices.ContainsFunc(tiers, func(tier []string) bool { return slices.Contains(tier, announce) }) {
+		tiers = append([][]string{{announce}}, tiers...)
+	}
+	return tiers
+}
+
+func bencodeString(dict map[string]any, key string) string {
+	value, _ := dict[key].(string)
+	return value
+}
+
+func bencodeInt(dict map[string]any, key string) int64 {
+	value, _ := dict[key].(int64)
+	return value
+}
+
+func bencodeStrings(list []any) []string {
+	var result []string
+	for _, item := range list {
+		if value, ok := item.(string); ok && value != "" {
+			result = append(result, value)
+		}
+	}
+	return result
+}
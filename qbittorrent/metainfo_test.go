@@ -0,0 +1,190 @@
+package qbittorrent
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/hex"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// bencodeEncode encode the test fixtures, the keys of the dictionaries are sorted
+func bencodeEncode(v any) []byte {
+	var buf bytes.Buffer
+	var encode func(v any)
+	encode = func(v any) {
+		switch v := v.(type) {
+		case int:
+			buf.WriteString("i" + strconv.Itoa(v) + "e")
+		case string:
+			buf.WriteString(strconv.Itoa(len(v)) + ":" + v)
+		case []any:
+			buf.WriteByte('l')
+			for _, item := range v {
+				encode(item)
+			}
+			buf.WriteByte('e')
+		case map[string]any:
+			buf.WriteByte('d')
+			for _, key := range sortedKeys(v) {
+				encode(key)
+				encode(v[key])
+			}
+			buf.WriteByte('e')
+		}
+	}
+	encode(v)
+	return buf.Bytes()
+}
+
+func TestParseMetainfo_V1(t *testing.T) {
+	data := []byte("d8:announce36:https://tracker.example.org/announce4:infod6:lengthi1024e4:name9:bbbbb.iso" +
+		"12:piece lengthi16384e6:pieces20:aaaaaaaaaaaaaaaaaaaa7:privatei1eee")
+	m, err := ParseMetainfo(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sum := sha1.Sum([]byte("d6:lengthi1024e4:name9:bbbbb.iso12:piece lengthi16384e6:pieces20:aaaaaaaaaaaaaaaaaaaa7:privatei1ee"))
+	if m.InfoHashV1 != hex.EncodeToString(sum[:]) || m.Hash() != m.InfoHashV1 || m.IsV2() || m.IsHybrid() {
+		t.Fatalf("unexpected hashes: %s %s", m.InfoHashV1, m.InfoHashV2)
+	}
+	if m.Name != "bbbbb.iso" || m.PieceLength != 16384 || !m.Private || m.Length != 1024 {
+		t.Fatalf("unexpected metainfo: %+v", m)
+	}
+	if len(m.Files) != 1 || *m.Files[0] != (MetainfoFile{Path: "bbbbb.iso", Length: 1024}) {
+		t.Fatalf("unexpected files: %v", m.Files)
+	}
+	if !reflect.DeepEqual(m.Trackers, [][]string{{"https://tracker.example.org/announce"}}) {
+		t.Fatalf("unexpected trackers: %v", m.Trackers)
+	}
+}
+
+func TestParseMetainfo_MultiFile(t *testing.T) {
+	info := map[string]any{
+		"name":         "album",
+		"piece length": 32768,
+		"pieces":       strings.Repeat("a", 40),
+		"files": []any{
+			map[string]any{"length": 100, "path": []any{"cd1", "01.flac"}},
+			map[string]any{"length": 200, "path": []any{"cover.jpg"}},
+		},
+	}
+	data := bencodeEncode(map[string]any{
+		"announce":      "https://a.example.org/announce",
+		"announce-list": []any{[]any{"https://a.example.org/announce", "https://b.example.org/announce"}, []any{"udp://c.example.org:80"}},
+		"url-list":      []any{"https://seed.example.org/"},
+		"comment":       "test",
+		"created by":    "qBittorrent v4.6.5",
+		"info":          info,
+	})
+	m, err := ParseMetainfo(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sum := sha1.Sum(bencodeEncode(info))
+	if m.Hash() != hex.EncodeToString(sum[:]) {
+		t.Fatalf("unexpected hash: %s", m.Hash())
+	}
+	expected := []*MetainfoFile{{Path: "album/cd1/01.flac", Length: 100}, {Path: "album/cover.jpg", Length: 200}}
+	if !reflect.DeepEqual(m.Files, expected) || m.Length != 300 {
+		t.Fatalf("unexpected files: %v", m.Files)
+	}
+	trackers := [][]string{{"https://a.example.org/announce", "https://b.example.org/announce"}, {"udp://c.example.org:80"}}
+	if !reflect.DeepEqual(m.Trackers, trackers) {
+		t.Fatalf("unexpected trackers: %v", m.Trackers)
+	}
+	if !reflect.DeepEqual(m.WebSeeds, []string{"https://seed.example.org/"}) || m.Comment != "test" || m.CreatedBy != "qBittorrent v4.6.5" {
+		t.Fatalf("unexpected metainfo: %+v", m)
+	}
+}
+
+func TestParseMetainfo_V2(t *testing.T) {
+	leaf := func(length int) map[string]any {
+		return map[string]any{"": map[string]any{"length": length, "pieces root": strings.Repeat("r", 32)}}
+	}
+	info := map[string]any{
+		"name":         "album",
+		"piece length": 16384,
+		"meta version": 2,
+		"file tree": map[string]any{
+			"cd1":       map[string]any{"01.flac": leaf(100)},
+			"cover.jpg": leaf(200),
+		},
+	}
+	m, err := ParseMetainfo(bencodeEncode(map[string]any{"info": info, "piece layers": map[string]any{}}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sum := sha256.Sum256(bencodeEncode(info))
+	if m.InfoHashV2 != hex.EncodeToString(sum[:]) || m.IsV1() || m.Hash() != hex.EncodeToString(sum[:20]) {
+		t.Fatalf("unexpected hashes: %s %s", m.InfoHashV1, m.InfoHashV2)
+	}
+	expected := []*MetainfoFile{{Path: "album/cd1/01.flac", Length: 100}, {Path: "album/cover.jpg", Length: 200}}
+	if !reflect.DeepEqual(m.Files, expected) {
+		t.Fatalf("unexpected files: %v", m.Files)
+	}
+
+	single := map[string]any{
+		"name":         "debian.iso",
+		"piece length": 16384,
+		"meta version": 2,
+		"file tree":    map[string]any{"debian.iso": leaf(1024)},
+	}
+	m, err = ParseMetainfo(bencodeEncode(map[string]any{"info": single}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(m.Files) != 1 || *m.Files[0] != (MetainfoFile{Path: "debian.iso", Length: 1024}) {
+		t.Fatalf("unexpected files: %v", m.Files)
+	}
+}
+
+func TestParseMetainfo_Hybrid(t *testing.T) {
+	info := map[string]any{
+		"name":         "album",
+		"piece length": 16384,
+		"meta version": 2,
+		"pieces":       strings.Repeat("a", 20),
+		"file tree":    map[string]any{"a.flac": map[string]any{"": map[string]any{"length": 100}}},
+		"files": []any{
+			map[string]any{"length": 100, "path": []any{"a.flac"}},
+			map[string]any{"length": 16284, "path": []any{".pad", "16284"}, "attr": "p"},
+		},
+	}
+	m, err := ParseMetainfo(bencodeEncode(map[string]any{"info": info}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	v1, v2 := sha1.Sum(bencodeEncode(info)), sha256.Sum256(bencodeEncode(info))
+	if !m.IsHybrid() || m.InfoHashV1 != hex.EncodeToString(v1[:]) || m.InfoHashV2 != hex.EncodeToString(v2[:]) {
+		t.Fatalf("unexpected hashes: %s %s", m.InfoHashV1, m.InfoHashV2)
+	}
+	if m.Hash() != m.InfoHashV1 {
+		t.Fatalf("expected the v1 hash, got %s", m.Hash())
+	}
+	if len(m.Files) != 1 || m.Files[0].Path != "album/a.flac" || m.Length != 100 {
+		t.Fatalf("expected the padding file to be skipped, got %v", m.Files)
+	}
+}
+
+func TestParseMetainfo_Invalid(t *testing.T) {
+	for _, data := range []string{
+		"",
+		"le",
+		"d8:announce3:urle",
+		"d4:infod4:name1:a12:piece lengthi16384eee",
+		"d4:infod6:lengthi1e4:name1:a12:piece lengthi16384e6:pieces3:abcee",
+		"d4:infod6:lengthi1e12:piece lengthi16384e6:pieces0:ee",
+		"d4:infod4:name1:a12:piece lengthi16384e12:meta versioni2eee",
+	} {
+		if _, err := ParseMetainfo([]byte(data)); err == nil {
+			t.Fatalf("%q: expected an error", data)
+		}
+	}
+	if hash := (&Metainfo{}).Hash(); hash != "" {
+		t.Fatalf("expected no hash, got %s", hash)
+	}
+}
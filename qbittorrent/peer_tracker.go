@@ -0,0 +1,234 @@
+package qbittorrent
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/bytedance/sonic"
+)
+
+const (
+	defaultPeerRateWindow   = 10 * time.Second
+	defaultPeerPollInterval = 2 * time.Second
+)
+
+type PeerEventType string
+
+const (
+	EventPeerJoined  PeerEventType = "peer_joined"  // a peer connected
+	EventPeerLeft    PeerEventType = "peer_left"    // a peer disconnected
+	EventPeerUpdated PeerEventType = "peer_updated" // some fields of a peer changed
+)
+
+// PeerEvent is a change of the peer table detected by PeerTracker
+type PeerEvent struct {
+	Type PeerEventType
+	Key  string // key of the peer, "ip:port"
+	// Peer current peer, the last known peer for EventPeerLeft
+	Peer *TrackedPeer
+	// Previous peer before the change, nil for EventPeerJoined
+	Previous *TrackedPeer
+}
+
+// TrackedPeer is a peer of the table maintained by PeerTracker
+type TrackedPeer struct {
+	SyncTorrentPeer
+	DownloadRate float64 // bytes per second downloaded from the peer, averaged over the rate window
+	UploadRate   float64 // bytes per second uploaded to the peer, averaged over the rate window
+}
+
+type PeerTrackerOption struct {
+	// RateWindow duration the transfer rates are averaged over, default 10s
+	RateWindow time.Duration
+}
+
+// PeerTracker maintains the full peer table of a torrent from the partial updates of
+// /api/v2/sync/torrentPeers. A PeerTracker is safe for concurrent use.
+type PeerTracker struct {
+	source Sync
+	hash   string
+	window time.Duration
+	now    func() time.Time
+	// pollMu serializes Sync so that two polls never use the same rid
+	pollMu sync.Mutex
+
+	mu      sync.RWMutex
+	rid     int
+	raw     map[string]map[string]any
+	peers   map[string]*TrackedPeer
+	samples map[string][]peerSample
+}
+
+// peerSample total transferred bytes of a peer at a point in time
+type peerSample struct {
+	at         time.Time
+	downloaded int
+	uploaded   int
+}
+
+// NewPeerTracker create a PeerTracker for the torrent, opt may be nil
+func NewPeerTracker(s Sync, hash string, opt *PeerTrackerOption) *PeerTracker {
+	var tracker = &PeerTracker{
+		source:  s,
+		hash:    hash,
+		window:  defaultPeerRateWindow,
+		now:     time.Now,
+		raw:     make(map[string]map[string]any),
+		peers:   make(map[string]*TrackedPeer),
+		samples: make(map[string][]peerSample),
+	}
+	if opt != nil && opt.RateWindow > 0 {
+		tracker.window = opt.RateWindow
+	}
+	return tracker
+}
+
+// Sync fetch the changes since the last call, apply them and returns the resulting events
+func (t *PeerTracker) Sync(ctx context.Context) ([]PeerEvent, error) {
+	t.pollMu.Lock()
+	defer t.pollMu.Unlock()
+	data, err := t.source.TorrentPeersDataContext(ctx, t.hash, t.Rid())
+	if err != nil {
+		return nil, err
+	}
+	return t.Apply(data)
+}
+
+// Run poll every interval until ctx is canceled and pass the events to handler, it returns ctx.Err()
+// or the first error of a poll. interval defaults to 2s if it is not positive
+func (t *PeerTracker) Run(ctx context.Context, interval time.Duration, handler func(PeerEvent)) error {
+	if interval <= 0 {
+		interval = defaultPeerPollInterval
+	}
+	var ticker = time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		events, err := t.Sync(ctx)
+		if err != nil {
+			return err
+		}
+		for _, event := range events {
+			handler(event)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Apply merge a response of /api/v2/sync/torrentPeers into the peer table, it is called by Sync
+// and only needed when polling TorrentPeersData manually
+func (t *PeerTracker) Apply(data *SyncTorrentPeers) ([]PeerEvent, error) {
+	var raw = data.raw
+	if raw == nil {
+		var err error
+		if raw, err = sonic.Marshal(data); err != nil {
+			return nil, err
+		}
+	}
+	var delta peersDelta
+	if err := sonic.Unmarshal(raw, &delta); err != nil {
+		return nil, err
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	var now = t.now()
+	var events []PeerEvent
+	var previous = t.peers
+	t.rid = delta.Rid
+	if delta.FullUpdate {
+		t.raw = make(map[string]map[string]any)
+		t.peers = make(map[string]*TrackedPeer)
+		// peers missing from a full update have left
+		for _, key := range sortedKeys(previous) {
+			if _, ok := delta.Peers[key]; !ok {
+				events = append(events, PeerEvent{Type: EventPeerLeft, Key: key, Peer: previous[key], Previous: previous[key]})
+				delete(t.samples, key)
+			}
+		}
+	}
+
+	for _, key := range sortedKeys(delta.Peers) {
+		old, ok := previous[key]
+		t.raw[key] = mergeFields(t.raw[key], delta.Peers[key])
+		var peer = new(TrackedPeer)
+		if err := decodeFields(t.raw[key], &peer.SyncTorrentPeer); err != nil {
+			return nil, err
+		}
+		peer.DownloadRate, peer.UploadRate = t.rates(key, now, peer)
+		t.peers[key] = peer
+		switch {
+		case !ok:
+			events = append(events, PeerEvent{Type: EventPeerJoined, Key: key, Peer: peer})
+		case old.SyncTorrentPeer != peer.SyncTorrentPeer:
+			events = append(events, PeerEvent{Type: EventPeerUpdated, Key: key, Peer: peer, Previous: old})
+		}
+	}
+	// unchanged peers are left out of partial updates, their rates decay with the last counters
+	for _, key := range sortedKeys(t.peers) {
+		if _, ok := delta.Peers[key]; ok {
+			continue
+		}
+		var peer = *t.peers[key]
+		peer.DownloadRate, peer.UploadRate = t.rates(key, now, &peer)
+		t.peers[key] = &peer
+	}
+	for _, key := range delta.PeersRemoved {
+		if peer, ok := t.peers[key]; ok {
+			events = append(events, PeerEvent{Type: EventPeerLeft, Key: key, Peer: peer, Previous: peer})
+		}
+		delete(t.raw, key)
+		delete(t.peers, key)
+		delete(t.samples, key)
+	}
+	return events, nil
+}
+
+// Rid returns the response id of the last applied update, 0 before the first update
+func (t *PeerTracker) Rid() int {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.rid
+}
+
+// Peers returns a copy of the peer table, the key is "ip:port"
+func (t *PeerTracker) Peers() map[string]*TrackedPeer {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	var peers = make(map[string]*TrackedPeer, len(t.peers))
+	for key, peer := range t.peers {
+		p := *peer
+		peers[key] = &p
+	}
+	return peers
+}
+
+// rates record a sample of the peer and returns the download and upload rates over the window,
+// the caller must hold mu
+func (t *PeerTracker) rates(key string, now time.Time, peer *TrackedPeer) (float64, float64) {
+	var samples = append(t.samples[key], peerSample{at: now, downloaded: peer.Downloaded, uploaded: peer.Uploaded})
+	// drop the samples outside of the window, the last two samples are always kept
+	for len(samples) > 2 && now.Sub(samples[1].at) >= t.window {
+		samples = samples[1:]
+	}
+	t.samples[key] = samples
+
+	var first, last = samples[0], samples[len(samples)-1]
+	var elapsed = last.at.Sub(first.at).Seconds()
+	if elapsed <= 0 {
+		return 0, 0
+	}
+	return float64(last.downloaded-first.downloaded) / elapsed, float64(last.uploaded-first.uploaded) / elapsed
+}
+
+// peersDelta is the raw form of a /api/v2/sync/torrentPeers response
+type peersDelta struct {
+	Rid          int                       `json:"rid"`
+	FullUpdate   bool                      `json:"full_update"`
+	Peers        map[string]map[string]any `json:"peers"`
+	PeersRemoved []string                  `json:"peers_removed"`
+}
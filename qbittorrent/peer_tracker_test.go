@@ -0,0 +1,106 @@
+package qbittorrent
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/xuthus5/qbittorrent-client-go/qbittorrent/qbittest"
+)
+
+func TestPeerTracker(t *testing.T) {
+	client, server := newTestClient(t)
+	server.AddTorrent(&qbittest.Torrent{
+		Hash: "8c212779b4abde7c6bc608063a0d008b7e40ce32",
+		Peers: []*qbittest.Peer{
+			{IP: "10.0.0.2", Port: 51413, Client: "qBittorrent 4.6.5", Downloaded: 1000},
+			{IP: "2001:db8::1", Port: 6881, Client: "Transmission 4.0.6"},
+		},
+	})
+
+	var now = time.Unix(1700000000, 0)
+	tracker := NewPeerTracker(client.Sync(), "8c212779b4abde7c6bc608063a0d008b7e40ce32", nil)
+	tracker.now = func() time.Time { return now }
+	events, err := tracker.Sync(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 2 || events[0].Type != EventPeerJoined || events[1].Key != "[2001:db8::1]:6881" {
+		t.Fatalf("unexpected events: %+v", events)
+	}
+
+	now = now.Add(2 * time.Second)
+	server.UpdateTorrent("8c212779b4abde7c6bc608063a0d008b7e40ce32", func(torrent *qbittest.Torrent) {
+		torrent.Peers[0].Downloaded = 5000
+		torrent.Peers = torrent.Peers[:1]
+	})
+	events, err = tracker.Sync(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 2 || events[0].Type != EventPeerUpdated || events[1].Type != EventPeerLeft {
+		t.Fatalf("unexpected events: %+v", events)
+	}
+	peers := tracker.Peers()
+	peer := peers["10.0.0.2:51413"]
+	if len(peers) != 1 || peer.Client != "qBittorrent 4.6.5" || peer.DownloadRate != 2000 {
+		t.Fatalf("unexpected peer table: %+v", peers)
+	}
+}
+
+func TestPeerTracker_IdlePeer(t *testing.T) {
+	client, server := newTestClient(t)
+	const hash = "8c212779b4abde7c6bc608063a0d008b7e40ce32"
+	server.AddTorrent(&qbittest.Torrent{
+		Hash:  hash,
+		Peers: []*qbittest.Peer{{IP: "10.0.0.2", Port: 51413, Downloaded: 1000}},
+	})
+
+	var now = time.Unix(1700000000, 0)
+	tracker := NewPeerTracker(client.Sync(), hash, nil)
+	tracker.now = func() time.Time { return now }
+	if _, err := tracker.Sync(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	now = now.Add(2 * time.Second)
+	server.UpdateTorrent(hash, func(torrent *qbittest.Torrent) {
+		torrent.Peers[0].Downloaded = 5000
+	})
+	if _, err := tracker.Sync(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if rate := tracker.Peers()["10.0.0.2:51413"].DownloadRate; rate != 2000 {
+		t.Fatalf("unexpected rate: %v", rate)
+	}
+
+	// the idle peer is left out of the partial updates
+	now = now.Add(60 * time.Second)
+	events, err := tracker.Sync(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 0 {
+		t.Fatalf("unexpected events: %+v", events)
+	}
+	if rate := tracker.Peers()["10.0.0.2:51413"].DownloadRate; rate != 0 {
+		t.Fatalf("expected the rate of the idle peer to decay to 0, got %v", rate)
+	}
+}
+
+func TestPeerTracker_RunDefaultInterval(t *testing.T) {
+	client, server := newTestClient(t)
+	const hash = "8c212779b4abde7c6bc608063a0d008b7e40ce32"
+	server.AddTorrent(&qbittest.Torrent{Hash: hash, Peers: []*qbittest.Peer{{IP: "10.0.0.2", Port: 51413}}})
+
+	tracker := NewPeerTracker(client.Sync(), hash, nil)
+	ctx, cancel := context.WithCancel(context.Background())
+	var events []PeerEvent
+	err := tracker.Run(ctx, 0, func(event PeerEvent) {
+		events = append(events, event)
+		cancel()
+	})
+	if !errors.Is(err, context.Canceled) || len(events) != 1 {
+		t.Fatalf("unexpected result: %v %+v", err, events)
+	}
+}
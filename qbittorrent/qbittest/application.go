@@ -0,0 +1,253 @@
+package qbittest
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/bytedance/sonic"
+)
+
+// LogEntry is an entry of the main log or the peer log
+type LogEntry struct {
+	Id        int
+	Timestamp int64
+	// Type Log::NORMAL: 1, Log::INFO: 2, Log::WARNING: 4, Log::CRITICAL: 8, unused by the peer log
+	Type    int
+	Message string
+	IP      string
+	Blocked bool
+	Reason  string
+}
+
+// AddLog append a message to the main log, returns the id of the message
+func (s *Server) AddLog(logType int, message string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var entry = &LogEntry{Id: len(s.logs), Timestamp: time.Now().Unix(), Type: logType, Message: message}
+	s.logs = append(s.logs, entry)
+	return entry.Id
+}
+
+// AddPeerLog append an entry to the peer log, returns the id of the entry
+func (s *Server) AddPeerLog(ip string, blocked bool, reason string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var entry = &LogEntry{Id: len(s.peerLogs), Timestamp: time.Now().Unix(), IP: ip, Blocked: blocked, Reason: reason}
+	s.peerLogs = append(s.peerLogs, entry)
+	return entry.Id
+}
+
+// Preferences returns a copy of the application preferences
+func (s *Server) Preferences() map[string]any {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var prefs = make(map[string]any, len(s.preferences))
+	for key, value := range s.preferences {
+		prefs[key] = value
+	}
+	return prefs
+}
+
+// BannedPeers returns the peers banned by /api/v2/transfer/banPeers
+func (s *Server) BannedPeers() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.bannedPeers...)
+}
+
+func defaultPreferences() map[string]any {
+	return map[string]any{
+		"locale":                     "en",
+		"save_path":                  DefaultSavePath,
+		"temp_path":                  DefaultSavePath + "/temp",
+		"temp_path_enabled":          false,
+		"queueing_enabled":           true,
+		"max_active_downloads":       3,
+		"max_active_torrents":        5,
+		"max_active_uploads":         3,
+		"dl_limit":                   0,
+		"up_limit":                   0,
+		"alt_dl_limit":               10240,
+		"alt_up_limit":               10240,
+		"listen_port":                6881,
+		"dht":                        true,
+		"pex":                        true,
+		"lsd":                        true,
+		"file_log_age":               1,
+		"refresh_interval":           1500,
+		"web_ui_port":                8080,
+		"web_ui_username":            DefaultUsername,
+		"web_ui_max_auth_fail_count": 5,
+		"web_ui_session_timeout":     3600,
+		"bypass_local_auth":          false,
+		"torrent_content_layout":     "Original",
+		"torrent_stop_condition":     "None",
+	}
+}
+
+func (s *Server) registerApplication() {
+	s.handle("app/version", func(w http.ResponseWriter, r *http.Request) {
+		writeText(w, s.version)
+	})
+	s.handle("app/webapiVersion", func(w http.ResponseWriter, r *http.Request) {
+		writeText(w, s.webAPIVersion)
+	})
+	s.handle("app/buildInfo", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, map[string]any{
+			"bitness":    64,
+			"boost":      "1.83.0",
+			"libtorrent": "2.0.9.0",
+			"openssl":    "3.1.4",
+			"qt":         "6.6.1",
+			"zlib":       "1.3",
+		})
+	})
+	s.handle("app/shutdown", func(w http.ResponseWriter, r *http.Request) {})
+	s.handle("app/preferences", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, s.preferences)
+	})
+	s.handle("app/setPreferences", func(w http.ResponseWriter, r *http.Request) {
+		var prefs map[string]any
+		if err := sonic.Unmarshal([]byte(r.FormValue("json")), &prefs); err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		for key, value := range prefs {
+			s.preferences[key] = value
+		}
+	})
+	s.handle("app/defaultSavePath", func(w http.ResponseWriter, r *http.Request) {
+		writeText(w, s.defaultSavePath())
+	})
+}
+
+func (s *Server) registerLog() {
+	s.handle("log/main", func(w http.ResponseWriter, r *http.Request) {
+		var types = map[int]bool{
+			1: r.FormValue("normal") != "false",
+			2: r.FormValue("info") != "false",
+			4: r.FormValue("warning") != "false",
+			8: r.FormValue("critical") != "false",
+		}
+		var entries = make([]map[string]any, 0, len(s.logs))
+		for _, entry := range s.logs {
+			if entry.Id <= lastKnownId(r) || !types[entry.Type] {
+				continue
+			}
+			entries = append(entries, map[string]any{
+				"id":        entry.Id,
+				"timestamp": entry.Timestamp,
+				"type":      entry.Type,
+				"message":   entry.Message,
+			})
+		}
+		writeJSON(w, entries)
+	})
+	s.handle("log/peers", func(w http.ResponseWriter, r *http.Request) {
+		var entries = make([]map[string]any, 0, len(s.peerLogs))
+		for _, entry := range s.peerLogs {
+			if entry.Id <= lastKnownId(r) {
+				continue
+			}
+			entries = append(entries, map[string]any{
+				"id":        entry.Id,
+				"timestamp": entry.Timestamp,
+				"ip":        entry.IP,
+				"blocked":   entry.Blocked,
+				"reason":    entry.Reason,
+			})
+		}
+		writeJSON(w, entries)
+	})
+}
+
+func lastKnownId(r *http.Request) int {
+	id, err := strconv.Atoi(r.FormValue("last_known_id"))
+	if err != nil {
+		return -1
+	}
+	return id
+}
+
+func (s *Server) registerTransfer() {
+	s.handle("transfer/info", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, s.transferInfo())
+	})
+	s.handle("transfer/banPeers", func(w http.ResponseWriter, r *http.Request) {
+		s.bannedPeers = append(s.bannedPeers, splitList(r.FormValue("peers"), "|")...)
+	})
+	s.handle("transfer/speedLimitsMode", func(w http.ResponseWriter, r *http.Request) {
+		if s.altSpeedLimits {
+			writeText(w, "1")
+			return
+		}
+		writeText(w, "0")
+	})
+	s.handle("transfer/toggleSpeedLimitsMode", func(w http.ResponseWriter, r *http.Request) {
+		s.altSpeedLimits = !s.altSpeedLimits
+	})
+	s.handle("transfer/uploadLimit", func(w http.ResponseWriter, r *http.Request) {
+		writeText(w, strconv.Itoa(s.upRateLimit))
+	})
+	s.handle("transfer/setUploadLimit", func(w http.ResponseWriter, r *http.Request) {
+		s.upRateLimit, _ = strconv.Atoi(r.FormValue("limit"))
+	})
+	s.handle("transfer/downloadLimit", func(w http.ResponseWriter, r *http.Request) {
+		writeText(w, strconv.Itoa(s.dlRateLimit))
+	})
+	s.handle("transfer/setDownloadLimit", func(w http.ResponseWriter, r *http.Request) {
+		s.dlRateLimit, _ = strconv.Atoi(r.FormValue("limit"))
+	})
+}
+
+// transferInfo serialize the global transfer info the same way as /api/v2/transfer/info
+func (s *Server) transferInfo() map[string]any {
+	var dlSpeed, upSpeed, downloaded, uploaded int64
+	for _, t := range s.torrents {
+		dlSpeed += t.DlSpeed
+		upSpeed += t.UpSpeed
+		downloaded += t.Downloaded
+		uploaded += t.Uploaded
+	}
+	return map[string]any{
+		"connection_status":    "connected",
+		"dht_nodes":            0,
+		"dl_info_data":         downloaded,
+		"dl_info_speed":        dlSpeed,
+		"dl_rate_limit":        s.dlRateLimit,
+		"up_info_data":         uploaded,
+		"up_info_speed":        upSpeed,
+		"up_rate_limit":        s.upRateLimit,
+		"queueing":             s.queueingEnabled(),
+		"use_alt_speed_limits": s.altSpeedLimits,
+		"refresh_interval":     s.preferences["refresh_interval"],
+	}
+}
+
+// serverState serialize the server state of /api/v2/sync/maindata
+func (s *Server) serverState() map[string]any {
+	var state = s.transferInfo()
+	var downloaded, _ = state["dl_info_data"].(int64)
+	var uploaded, _ = state["up_info_data"].(int64)
+	var ratio = "0.00"
+	if downloaded > 0 {
+		ratio = strconv.FormatFloat(float64(uploaded)/float64(downloaded), 'f', 2, 64)
+	}
+	state["alltime_dl"] = downloaded
+	state["alltime_ul"] = uploaded
+	state["global_ratio"] = ratio
+	state["free_space_on_disk"] = int64(1 << 40)
+	state["average_time_queue"] = 0
+	state["queued_io_jobs"] = 0
+	state["read_cache_hits"] = "0"
+	state["read_cache_overload"] = "0"
+	state["write_cache_overload"] = "0"
+	state["total_buffers_size"] = 0
+	state["total_peer_connections"] = 0
+	state["total_queued_size"] = 0
+	state["total_wasted_session"] = 0
+	state["last_external_address_v4"] = ""
+	state["last_external_address_v6"] = ""
+	return state
+}
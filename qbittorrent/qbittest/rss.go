@@ -0,0 +1,236 @@
+package qbittest
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/bytedance/sonic"
+)
+
+// rssSeparator separates the path of rss items
+const rssSeparator = `\`
+
+// AddRSSArticle append an article to a feed, returns false if the feed does not exist
+func (s *Server) AddRSSArticle(feedPath, id, title, torrentURL string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	feed, ok := s.rssItem(feedPath).(map[string]any)
+	if !ok || !isFeed(feed) {
+		return false
+	}
+	articles, _ := feed["articles"].([]any)
+	feed["articles"] = append(articles, map[string]any{
+		"id":          id,
+		"title":       title,
+		"torrentURL":  torrentURL,
+		"isRead":      false,
+		"date":        "",
+		"description": "",
+	})
+	return true
+}
+
+func isFeed(item map[string]any) bool {
+	_, ok := item["uid"]
+	return ok
+}
+
+// rssItem returns the folder or feed at the path, the root folder is returned for an empty path
+func (s *Server) rssItem(itemPath string) any {
+	var item any = s.rssItems
+	if itemPath == "" {
+		return item
+	}
+	for _, name := range strings.Split(itemPath, rssSeparator) {
+		folder, ok := item.(map[string]any)
+		if !ok || isFeed(folder) {
+			return nil
+		}
+		if item, ok = folder[name]; !ok {
+			return nil
+		}
+	}
+	return item
+}
+
+// rssParent returns the parent folder and the name of the item at the path
+func (s *Server) rssParent(itemPath string) (map[string]any, string) {
+	var parent, name = "", itemPath
+	if i := strings.LastIndex(itemPath, rssSeparator); i >= 0 {
+		parent, name = itemPath[:i], itemPath[i+1:]
+	}
+	folder, ok := s.rssItem(parent).(map[string]any)
+	if !ok || isFeed(folder) || name == "" {
+		return nil, ""
+	}
+	return folder, name
+}
+
+// rssFeeds returns all feeds under the item
+func rssFeeds(item any) []map[string]any {
+	folder, ok := item.(map[string]any)
+	if !ok {
+		return nil
+	}
+	if isFeed(folder) {
+		return []map[string]any{folder}
+	}
+	var feeds []map[string]any
+	for _, name := range sortedKeys(folder) {
+		feeds = append(feeds, rssFeeds(folder[name])...)
+	}
+	return feeds
+}
+
+// rssItemsView serialize the items tree, articles are only included if withData is true
+func rssItemsView(item map[string]any, withData bool) map[string]any {
+	var view = make(map[string]any, len(item))
+	for name, value := range item {
+		child, _ := value.(map[string]any)
+		switch {
+		case isFeed(child) && withData:
+			view[name] = child
+		case isFeed(child):
+			view[name] = map[string]any{"uid": child["uid"], "url": child["url"]}
+		default:
+			view[name] = rssItemsView(child, withData)
+		}
+	}
+	return view
+}
+
+func (s *Server) registerRSS() {
+	s.handle("rss/addFolder", func(w http.ResponseWriter, r *http.Request) {
+		parent, name := s.rssParent(r.FormValue("path"))
+		if parent == nil {
+			writeError(w, http.StatusConflict, "Invalid path")
+			return
+		}
+		if _, ok := parent[name]; ok {
+			writeError(w, http.StatusConflict, "Item already exists")
+			return
+		}
+		parent[name] = make(map[string]any)
+	})
+	s.handle("rss/addFeed", func(w http.ResponseWriter, r *http.Request) {
+		var feedUrl, itemPath = r.FormValue("url"), r.FormValue("path")
+		if itemPath == "" {
+			itemPath = feedUrl
+		}
+		for _, feed := range rssFeeds(s.rssItems) {
+			if feed["url"] == feedUrl {
+				writeError(w, http.StatusConflict, "Feed already exists")
+				return
+			}
+		}
+		parent, name := s.rssParent(itemPath)
+		if parent == nil || feedUrl == "" {
+			writeError(w, http.StatusConflict, "Invalid path")
+			return
+		}
+		if _, ok := parent[name]; ok {
+			writeError(w, http.StatusConflict, "Item already exists")
+			return
+		}
+		parent[name] = map[string]any{
+			"uid":           newSessionID(),
+			"url":           feedUrl,
+			"title":         name,
+			"lastBuildDate": "",
+			"isLoading":     false,
+			"hasError":      false,
+			"articles":      []any{},
+		}
+	})
+	s.handle("rss/removeItem", func(w http.ResponseWriter, r *http.Request) {
+		parent, name := s.rssParent(r.FormValue("path"))
+		if _, ok := parent[name]; !ok {
+			writeError(w, http.StatusConflict, "Item does not exist")
+			return
+		}
+		delete(parent, name)
+	})
+	s.handle("rss/moveItem", func(w http.ResponseWriter, r *http.Request) {
+		src, srcName := s.rssParent(r.FormValue("itemPath"))
+		dest, destName := s.rssParent(r.FormValue("destPath"))
+		item, ok := src[srcName]
+		if !ok || dest == nil {
+			writeError(w, http.StatusConflict, "Item does not exist")
+			return
+		}
+		if _, ok := dest[destName]; ok {
+			writeError(w, http.StatusConflict, "Item already exists")
+			return
+		}
+		delete(src, srcName)
+		dest[destName] = item
+	})
+	s.handle("rss/items", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, rssItemsView(s.rssItems, r.FormValue("withData") == "true"))
+	})
+	s.handle("rss/markAsRead", func(w http.ResponseWriter, r *http.Request) {
+		var articleId = r.FormValue("articleId")
+		for _, feed := range rssFeeds(s.rssItem(r.FormValue("itemPath"))) {
+			articles, _ := feed["articles"].([]any)
+			for _, value := range articles {
+				if article, ok := value.(map[string]any); ok && (articleId == "" || article["id"] == articleId) {
+					article["isRead"] = true
+				}
+			}
+		}
+	})
+	s.handle("rss/refreshItem", func(w http.ResponseWriter, r *http.Request) {
+		if s.rssItem(r.FormValue("itemPath")) == nil {
+			writeError(w, http.StatusConflict, "Item does not exist")
+		}
+	})
+	s.handle("rss/setRule", func(w http.ResponseWriter, r *http.Request) {
+		var ruleName = r.FormValue("ruleName")
+		var rule map[string]any
+		if err := sonic.Unmarshal([]byte(r.FormValue("ruleDef")), &rule); err != nil || ruleName == "" {
+			writeError(w, http.StatusBadRequest, "Invalid rule")
+			return
+		}
+		s.rssRules[ruleName] = rule
+	})
+	s.handle("rss/renameRule", func(w http.ResponseWriter, r *http.Request) {
+		rule, ok := s.rssRules[r.FormValue("ruleName")]
+		if !ok {
+			writeError(w, http.StatusConflict, "Rule does not exist")
+			return
+		}
+		delete(s.rssRules, r.FormValue("ruleName"))
+		s.rssRules[r.FormValue("newRuleName")] = rule
+	})
+	s.handle("rss/removeRule", func(w http.ResponseWriter, r *http.Request) {
+		delete(s.rssRules, r.FormValue("ruleName"))
+	})
+	s.handle("rss/rules", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, s.rssRules)
+	})
+	s.handle("rss/matchingArticles", func(w http.ResponseWriter, r *http.Request) {
+		var matches = make(map[string][]string)
+		rule, ok := s.rssRules[r.FormValue("ruleName")]
+		if !ok {
+			writeJSON(w, matches)
+			return
+		}
+		var mustContain, _ = rule["mustContain"].(string)
+		var affected, _ = rule["affectedFeeds"].([]any)
+		for _, feed := range rssFeeds(s.rssItems) {
+			if len(affected) != 0 && !containsValue(affected, feed["url"]) {
+				continue
+			}
+			articles, _ := feed["articles"].([]any)
+			for _, value := range articles {
+				article, _ := value.(map[string]any)
+				title, _ := article["title"].(string)
+				if strings.Contains(strings.ToLower(title), strings.ToLower(mustContain)) {
+					name, _ := feed["title"].(string)
+					matches[name] = append(matches[name], title)
+				}
+			}
+		}
+		writeJSON(w, matches)
+	})
+}
@@ -0,0 +1,283 @@
+package qbittest
+
+import (
+	"net/http"
+	"path"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// maxRunningSearches number of search jobs qBittorrent runs concurrently
+const maxRunningSearches = 5
+
+// SearchResult is a result returned by the fake search engine
+type SearchResult struct {
+	DescrLink  string
+	FileName   string
+	FileSize   int64
+	FileUrl    string
+	NbLeechers int
+	NbSeeders  int
+	SiteUrl    string
+	// Plugin name of the plugin returning the result, empty means every plugin
+	Plugin string
+}
+
+// SearchPlugin is an installed search plugin
+type SearchPlugin struct {
+	Name       string
+	FullName   string
+	Url        string
+	Version    string
+	Enabled    bool
+	Categories []string
+}
+
+type searchJob struct {
+	id      int
+	pattern string
+	plugins []string
+	running bool
+	// scanned number of search results already matched against the job
+	scanned int
+	results []*SearchResult
+}
+
+// AddSearchPlugin install a search plugin
+func (s *Server) AddSearchPlugin(plugin *SearchPlugin) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	p := *plugin
+	s.searchPlugins[p.Name] = &p
+}
+
+// SearchPlugins returns the installed search plugins in name order
+func (s *Server) SearchPlugins() []*SearchPlugin {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var plugins []*SearchPlugin
+	for _, name := range sortedKeys(s.searchPlugins) {
+		p := *s.searchPlugins[name]
+		plugins = append(plugins, &p)
+	}
+	return plugins
+}
+
+// AddSearchResults add results to the fake search engine, a search job finds the results whose
+// file name contains the pattern, running jobs also find results added after they started
+func (s *Server) AddSearchResults(results ...*SearchResult) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, result := range results {
+		r := *result
+		s.searchResults = append(s.searchResults, &r)
+	}
+}
+
+// FinishSearch mark a search job as stopped, returns false if the job does not exist
+func (s *Server) FinishSearch(id int) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	job, ok := s.searchJobs[id]
+	if ok {
+		s.collect(job)
+		job.running = false
+	}
+	return ok
+}
+
+// SearchJobs returns the ids of the existing search jobs
+func (s *Server) SearchJobs() []int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return sortedIds(s.searchJobs)
+}
+
+func sortedIds(jobs map[int]*searchJob) []int {
+	var ids = make([]int, 0, len(jobs))
+	for id := range jobs {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	return ids
+}
+
+func (j *searchJob) status() string {
+	if j.running {
+		return "Running"
+	}
+	return "Stopped"
+}
+
+// lookupSearchJob returns the job of the "id" parameter, 404 is written if the job does not exist
+func (s *Server) lookupSearchJob(w http.ResponseWriter, r *http.Request) (*searchJob, bool) {
+	id, _ := strconv.Atoi(r.FormValue("id"))
+	job, ok := s.searchJobs[id]
+	if !ok {
+		writeError(w, http.StatusNotFound, "")
+		return nil, false
+	}
+	s.collect(job)
+	return job, true
+}
+
+// collect match the search results added since the last call against a running job
+func (s *Server) collect(job *searchJob) {
+	if !job.running {
+		return
+	}
+	for _, result := range s.searchResults[job.scanned:] {
+		if !strings.Contains(strings.ToLower(result.FileName), job.pattern) {
+			continue
+		}
+		if result.Plugin == "" || slices.Contains(job.plugins, result.Plugin) {
+			job.results = append(job.results, result)
+		}
+	}
+	job.scanned = len(s.searchResults)
+}
+
+// searchPluginNames resolve the "plugins" parameter of /api/v2/search/start
+func (s *Server) searchPluginNames(value string) []string {
+	var names []string
+	for _, name := range sortedKeys(s.searchPlugins) {
+		plugin := s.searchPlugins[name]
+		switch value {
+		case "all":
+			names = append(names, name)
+		case "enabled":
+			if plugin.Enabled {
+				names = append(names, name)
+			}
+		}
+	}
+	if value == "all" || value == "enabled" {
+		return names
+	}
+	return splitList(value, "|")
+}
+
+func (s *Server) registerSearch() {
+	s.handle("search/start", func(w http.ResponseWriter, r *http.Request) {
+		var pattern = strings.ToLower(r.FormValue("pattern"))
+		var plugins = s.searchPluginNames(r.FormValue("plugins"))
+		if pattern == "" || len(plugins) == 0 {
+			writeError(w, http.StatusBadRequest, "")
+			return
+		}
+		var running int
+		for _, job := range s.searchJobs {
+			if job.running {
+				running++
+			}
+		}
+		if running >= maxRunningSearches {
+			writeError(w, http.StatusConflict, "Unable to create more than 5 concurrent searches.")
+			return
+		}
+		s.searchId++
+		var job = &searchJob{id: s.searchId, pattern: pattern, plugins: plugins, running: true}
+		s.searchJobs[job.id] = job
+		writeJSON(w, map[string]any{"id": job.id})
+	})
+	s.handle("search/stop", func(w http.ResponseWriter, r *http.Request) {
+		if job, ok := s.lookupSearchJob(w, r); ok {
+			job.running = false
+		}
+	})
+	s.handle("search/status", func(w http.ResponseWriter, r *http.Request) {
+		var jobs = make([]map[string]any, 0, len(s.searchJobs))
+		if r.FormValue("id") != "" {
+			job, ok := s.lookupSearchJob(w, r)
+			if !ok {
+				return
+			}
+			jobs = append(jobs, map[string]any{"id": job.id, "status": job.status(), "total": len(job.results)})
+			writeJSON(w, jobs)
+			return
+		}
+		for _, id := range sortedIds(s.searchJobs) {
+			job := s.searchJobs[id]
+			s.collect(job)
+			jobs = append(jobs, map[string]any{"id": job.id, "status": job.status(), "total": len(job.results)})
+		}
+		writeJSON(w, jobs)
+	})
+	s.handle("search/results", func(w http.ResponseWriter, r *http.Request) {
+		job, ok := s.lookupSearchJob(w, r)
+		if !ok {
+			return
+		}
+		var total = len(job.results)
+		offset, _ := strconv.Atoi(r.FormValue("offset"))
+		if offset < 0 {
+			offset += total
+		}
+		if offset < 0 || offset > total {
+			writeError(w, http.StatusConflict, "Offset is out of range")
+			return
+		}
+		var results = job.results[offset:]
+		if limit, _ := strconv.Atoi(r.FormValue("limit")); limit > 0 && limit < len(results) {
+			results = results[:limit]
+		}
+		var items = make([]map[string]any, 0, len(results))
+		for _, result := range results {
+			items = append(items, map[string]any{
+				"descrLink":  result.DescrLink,
+				"fileName":   result.FileName,
+				"fileSize":   result.FileSize,
+				"fileUrl":    result.FileUrl,
+				"nbLeechers": result.NbLeechers,
+				"nbSeeders":  result.NbSeeders,
+				"siteUrl":    result.SiteUrl,
+			})
+		}
+		writeJSON(w, map[string]any{"results": items, "status": job.status(), "total": total})
+	})
+	s.handle("search/delete", func(w http.ResponseWriter, r *http.Request) {
+		if job, ok := s.lookupSearchJob(w, r); ok {
+			delete(s.searchJobs, job.id)
+		}
+	})
+	s.handle("search/plugins", func(w http.ResponseWriter, r *http.Request) {
+		var plugins = make([]map[string]any, 0, len(s.searchPlugins))
+		for _, name := range sortedKeys(s.searchPlugins) {
+			plugin := s.searchPlugins[name]
+			var categories = make([]map[string]any, 0, len(plugin.Categories))
+			for _, category := range plugin.Categories {
+				categories = append(categories, map[string]any{"id": category, "name": category})
+			}
+			plugins = append(plugins, map[string]any{
+				"enabled":             plugin.Enabled,
+				"fullName":            plugin.FullName,
+				"name":                plugin.Name,
+				"supportedCategories": categories,
+				"url":                 plugin.Url,
+				"version":             plugin.Version,
+			})
+		}
+		writeJSON(w, plugins)
+	})
+	s.handle("search/installPlugin", func(w http.ResponseWriter, r *http.Request) {
+		for _, source := range splitList(r.FormValue("sources"), "|") {
+			name := strings.TrimSuffix(path.Base(source), ".py")
+			s.searchPlugins[name] = &SearchPlugin{Name: name, FullName: name, Url: source, Version: "1.0", Enabled: true}
+		}
+	})
+	s.handle("search/uninstallPlugin", func(w http.ResponseWriter, r *http.Request) {
+		for _, name := range splitList(r.FormValue("names"), "|") {
+			delete(s.searchPlugins, name)
+		}
+	})
+	s.handle("search/enablePlugin", func(w http.ResponseWriter, r *http.Request) {
+		for _, name := range splitList(r.FormValue("names"), "|") {
+			if plugin, ok := s.searchPlugins[name]; ok {
+				plugin.Enabled = r.FormValue("enable") == "true"
+			}
+		}
+	})
+	s.handle("search/updatePlugins", func(w http.ResponseWriter, r *http.Request) {})
+}
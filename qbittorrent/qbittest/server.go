@@ -0,0 +1,320 @@
+// Package qbittest provides an in-memory fake of the qBittorrent WebUI API for tests.
+//
+// The fake keeps torrents, categories, tags, preferences, logs, rss items and search jobs in
+// memory and serves them over an httptest.Server, so a real qbittorrent.Client can be exercised
+// offline:
+//
+//	server := qbittest.NewServer()
+//	defer server.Close()
+//	server.AddTorrent(&qbittest.Torrent{Hash: "...", Name: "ubuntu.iso", Size: 1 << 30})
+//
+//	client, err := qbittorrent.NewClient(&qbittorrent.Config{
+//		Address:  server.URL,
+//		Username: qbittest.DefaultUsername,
+//		Password: qbittest.DefaultPassword,
+//	})
+package qbittest
+
+import (
+	"cmp"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/hex"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/bytedance/sonic"
+)
+
+const (
+	// DefaultUsername username accepted by a new Server
+	DefaultUsername = "admin"
+	// DefaultPassword password accepted by a new Server
+	DefaultPassword = "adminadmin"
+	// DefaultVersion application version reported by a new Server
+	DefaultVersion = "v4.6.5"
+	// DefaultWebAPIVersion webapi version reported by a new Server
+	DefaultWebAPIVersion = "2.9.3"
+	// DefaultSavePath default save path of a new Server
+	DefaultSavePath = "/downloads"
+
+	sessionCookieName = "SID"
+	// historySize number of sync snapshots kept for rid based partial updates
+	historySize = 32
+)
+
+// Server is a fake qBittorrent WebUI
+type Server struct {
+	// URL base url of the fake WebUI, use it as qbittorrent.Config.Address
+	URL string
+
+	server *httptest.Server
+	mux    *http.ServeMux
+
+	mu            sync.Mutex
+	username      string
+	password      string
+	version       string
+	webAPIVersion string
+	sessions      map[string]struct{}
+	bypassAuth    bool
+
+	torrents    map[string]*Torrent
+	categories  map[string]*Category
+	tags        map[string]struct{}
+	preferences map[string]any
+
+	logs        []*LogEntry
+	peerLogs    []*LogEntry
+	bannedPeers []string
+
+	altSpeedLimits bool
+	dlRateLimit    int
+	upRateLimit    int
+
+	rssItems map[string]any
+	rssRules map[string]map[string]any
+
+	searchId      int
+	searchJobs    map[int]*searchJob
+	searchPlugins map[string]*SearchPlugin
+	searchResults []*SearchResult
+
+	mainDataRid     int
+	mainDataHistory map[int]map[string]any
+	peersRid        int
+	peersHistory    map[int]map[string]any
+}
+
+// NewServer starts a fake qBittorrent WebUI, the caller should call Close when finished
+func NewServer() *Server {
+	s := newServer()
+	s.server = httptest.NewServer(s.mux)
+	s.URL = s.server.URL
+	return s
+}
+
+// NewTLSServer starts a fake qBittorrent WebUI over HTTPS, use Certificate to trust it
+func NewTLSServer() *Server {
+	s := newServer()
+	s.server = httptest.NewTLSServer(s.mux)
+	s.URL = s.server.URL
+	return s
+}
+
+// NewUnixServer starts a fake qBittorrent WebUI listening on the unix socket, the URL is
+// "unix://" followed by the socket path
+func NewUnixServer(socket string) (*Server, error) {
+	listener, err := net.Listen("unix", socket)
+	if err != nil {
+		return nil, err
+	}
+	s := newServer()
+	s.server = httptest.NewUnstartedServer(s.mux)
+	_ = s.server.Listener.Close()
+	s.server.Listener = listener
+	s.server.Start()
+	s.URL = "unix://" + socket
+	return s, nil
+}
+
+func newServer() *Server {
+	s := &Server{
+		mux:             http.NewServeMux(),
+		username:        DefaultUsername,
+		password:        DefaultPassword,
+		version:         DefaultVersion,
+		webAPIVersion:   DefaultWebAPIVersion,
+		sessions:        make(map[string]struct{}),
+		torrents:        make(map[string]*Torrent),
+		categories:      make(map[string]*Category),
+		tags:            make(map[string]struct{}),
+		preferences:     defaultPreferences(),
+		rssItems:        make(map[string]any),
+		rssRules:        make(map[string]map[string]any),
+		searchJobs:      make(map[int]*searchJob),
+		searchPlugins:   make(map[string]*SearchPlugin),
+		mainDataHistory: make(map[int]map[string]any),
+		peersHistory:    make(map[int]map[string]any),
+	}
+	s.registerAuth()
+	s.registerApplication()
+	s.registerLog()
+	s.registerSync()
+	s.registerTransfer()
+	s.registerTorrents()
+	s.registerRSS()
+	s.registerSearch()
+	return s
+}
+
+// Close shuts down the server
+func (s *Server) Close() {
+	s.server.Close()
+}
+
+// Certificate returns the certificate of a server started by NewTLSServer, nil otherwise
+func (s *Server) Certificate() *x509.Certificate {
+	return s.server.Certificate()
+}
+
+// SetCredentials change the username and password accepted by the server
+func (s *Server) SetCredentials(username, password string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.username, s.password = username, password
+}
+
+// SetVersion change the application and webapi version reported by the server
+func (s *Server) SetVersion(version, webAPIVersion string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.version, s.webAPIVersion = version, webAPIVersion
+}
+
+// SetBypassAuth accept requests without session, like bypass_auth_subnet_whitelist matching the client
+func (s *Server) SetBypassAuth(bypass bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.bypassAuth = bypass
+}
+
+// ExpireSessions invalidate all sessions, following requests are answered with 403 until login again
+func (s *Server) ExpireSessions() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sessions = make(map[string]struct{})
+}
+
+// endpointVersions webapi versions an endpoint exists in, since is inclusive and until is exclusive,
+// endpoints that are not listed exist in every version
+var endpointVersions = map[string]struct{ since, until string }{
+	"torrents/pause":   {until: "2.11.0"},
+	"torrents/resume":  {until: "2.11.0"},
+	"torrents/stop":    {since: "2.11.0"},
+	"torrents/start":   {since: "2.11.0"},
+	"torrents/setTags": {since: "2.11.4"},
+}
+
+// handle register an endpoint, handler is called with the server lock held
+func (s *Server) handle(path string, handler func(w http.ResponseWriter, r *http.Request)) {
+	s.mux.HandleFunc("/api/v2/"+path, func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if versions, ok := endpointVersions[path]; ok {
+			if (versions.since != "" && compareVersions(s.webAPIVersion, versions.since) < 0) ||
+				(versions.until != "" && compareVersions(s.webAPIVersion, versions.until) >= 0) {
+				writeError(w, http.StatusNotFound, "Not Found")
+				return
+			}
+		}
+		if !s.authorized(r) {
+			writeError(w, http.StatusForbidden, "Forbidden")
+			return
+		}
+		handler(w, r)
+	})
+}
+
+// compareVersions compare two dotted version numbers, a leading "v" is ignored
+func compareVersions(a, b string) int {
+	var as = strings.Split(strings.TrimPrefix(a, "v"), ".")
+	var bs = strings.Split(strings.TrimPrefix(b, "v"), ".")
+	for i := 0; i < max(len(as), len(bs)); i++ {
+		var x, y int
+		if i < len(as) {
+			x, _ = strconv.Atoi(as[i])
+		}
+		if i < len(bs) {
+			y, _ = strconv.Atoi(bs[i])
+		}
+		if x != y {
+			return cmp.Compare(x, y)
+		}
+	}
+	return 0
+}
+
+func (s *Server) authorized(r *http.Request) bool {
+	if s.bypassAuth || r.URL.Path == "/api/v2/auth/login" {
+		return true
+	}
+	cookie, err := r.Cookie(sessionCookieName)
+	if err != nil {
+		return false
+	}
+	_, ok := s.sessions[cookie.Value]
+	return ok
+}
+
+func (s *Server) registerAuth() {
+	s.handle("auth/login", func(w http.ResponseWriter, r *http.Request) {
+		if r.FormValue("username") != s.username || r.FormValue("password") != s.password {
+			writeText(w, "Fails.")
+			return
+		}
+		sid := newSessionID()
+		s.sessions[sid] = struct{}{}
+		http.SetCookie(w, &http.Cookie{Name: sessionCookieName, Value: sid, Path: "/", HttpOnly: true})
+		writeText(w, "Ok.")
+	})
+	s.handle("auth/logout", func(w http.ResponseWriter, r *http.Request) {
+		if cookie, err := r.Cookie(sessionCookieName); err == nil {
+			delete(s.sessions, cookie.Value)
+		}
+	})
+}
+
+func newSessionID() string {
+	var b [16]byte
+	_, _ = rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	data, err := sonic.Marshal(v)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(data)
+}
+
+func writeText(w http.ResponseWriter, text string) {
+	w.Header().Set("Content-Type", "text/plain; charset=UTF-8")
+	_, _ = w.Write([]byte(text))
+}
+
+func writeError(w http.ResponseWriter, code int, text string) {
+	w.Header().Set("Content-Type", "text/plain; charset=UTF-8")
+	w.WriteHeader(code)
+	_, _ = w.Write([]byte(text))
+}
+
+// splitList split a list parameter, empty items are dropped
+func splitList(value, sep string) []string {
+	var items []string
+	for _, item := range strings.Split(value, sep) {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// sortedKeys returns the keys of a map in order
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
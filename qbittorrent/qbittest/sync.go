@@ -0,0 +1,191 @@
+package qbittest
+
+import (
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/bytedance/sonic"
+)
+
+func (s *Server) registerSync() {
+	s.handle("sync/maindata", func(w http.ResponseWriter, r *http.Request) {
+		var current = normalize(s.mainData())
+		rid, _ := strconv.Atoi(r.FormValue("rid"))
+		s.mainDataRid++
+		s.remember(s.mainDataHistory, s.mainDataRid, current)
+
+		previous, ok := s.mainDataHistory[rid]
+		if rid == 0 || !ok {
+			var full = map[string]any{"rid": s.mainDataRid, "full_update": true}
+			for key, value := range current {
+				full[key] = value
+			}
+			writeJSON(w, full)
+			return
+		}
+		var delta = map[string]any{"rid": s.mainDataRid}
+		diffMaps(delta, previous, current, "torrents", "torrents_removed")
+		diffMaps(delta, previous, current, "categories", "categories_removed")
+		diffMaps(delta, previous, current, "trackers", "trackers_removed")
+		diffLists(delta, previous, current, "tags", "tags_removed")
+		if state := diffObject(previous["server_state"], current["server_state"]); len(state) != 0 {
+			delta["server_state"] = state
+		}
+		writeJSON(w, delta)
+	})
+	s.handle("sync/torrentPeers", func(w http.ResponseWriter, r *http.Request) {
+		t, ok := s.lookupTorrent(w, r)
+		if !ok {
+			return
+		}
+		var peers = make(map[string]any, len(t.Peers))
+		for _, peer := range t.Peers {
+			peers[peer.key()] = peer.info()
+		}
+		var current = normalize(map[string]any{"hash": t.Hash, "peers": peers})
+		rid, _ := strconv.Atoi(r.FormValue("rid"))
+		s.peersRid++
+		s.remember(s.peersHistory, s.peersRid, current)
+
+		previous, ok := s.peersHistory[rid]
+		if rid == 0 || !ok || previous["hash"] != current["hash"] {
+			writeJSON(w, map[string]any{"rid": s.peersRid, "full_update": true, "show_flags": true, "peers": peers})
+			return
+		}
+		var delta = map[string]any{"rid": s.peersRid, "show_flags": true}
+		diffMaps(delta, previous, current, "peers", "peers_removed")
+		writeJSON(w, delta)
+	})
+}
+
+// mainData build the full /api/v2/sync/maindata response of the current state
+func (s *Server) mainData() map[string]any {
+	var torrents = make(map[string]any, len(s.torrents))
+	var trackers = make(map[string][]string)
+	for _, hash := range sortedKeys(s.torrents) {
+		t := s.torrents[hash]
+		info := t.info()
+		delete(info, "hash")
+		torrents[hash] = info
+		for _, tracker := range t.Trackers {
+			trackers[tracker.URL] = append(trackers[tracker.URL], hash)
+		}
+	}
+	var categories = make(map[string]any, len(s.categories))
+	for name, category := range s.categories {
+		categories[name] = map[string]any{"name": category.Name, "savePath": category.SavePath}
+	}
+	return map[string]any{
+		"torrents":     torrents,
+		"categories":   categories,
+		"tags":         sortedKeys(s.tags),
+		"trackers":     trackers,
+		"server_state": s.serverState(),
+	}
+}
+
+// remember store a snapshot for later partial updates, old snapshots are dropped
+func (s *Server) remember(history map[int]map[string]any, rid int, snapshot map[string]any) {
+	history[rid] = snapshot
+	delete(history, rid-historySize)
+}
+
+// normalize convert v to plain json values so that snapshots can be compared
+func normalize(v map[string]any) map[string]any {
+	data, _ := sonic.Marshal(v)
+	var m map[string]any
+	_ = sonic.Unmarshal(data, &m)
+	return m
+}
+
+// diffMaps write the changed entries of a keyed collection to delta, entries are diffed field by field
+func diffMaps(delta, previous, current map[string]any, key, removedKey string) {
+	var prev, _ = previous[key].(map[string]any)
+	var cur, _ = current[key].(map[string]any)
+	var changed = make(map[string]any)
+	for id, value := range cur {
+		old, ok := prev[id]
+		if !ok {
+			changed[id] = value
+			continue
+		}
+		oldObject, isObject := old.(map[string]any)
+		newObject, _ := value.(map[string]any)
+		if !isObject || newObject == nil {
+			if !reflect.DeepEqual(old, value) {
+				changed[id] = value
+			}
+			continue
+		}
+		if diff := diffObject(oldObject, newObject); len(diff) != 0 {
+			changed[id] = diff
+		}
+	}
+	var removed []string
+	for id := range prev {
+		if _, ok := cur[id]; !ok {
+			removed = append(removed, id)
+		}
+	}
+	if len(changed) != 0 {
+		delta[key] = changed
+	}
+	if len(removed) != 0 {
+		delta[removedKey] = removed
+	}
+}
+
+// diffLists write the added and removed items of a string list to delta
+func diffLists(delta, previous, current map[string]any, key, removedKey string) {
+	var prev, _ = previous[key].([]any)
+	var cur, _ = current[key].([]any)
+	var added, removed []any
+	for _, item := range cur {
+		if !containsValue(prev, item) {
+			added = append(added, item)
+		}
+	}
+	for _, item := range prev {
+		if !containsValue(cur, item) {
+			removed = append(removed, item)
+		}
+	}
+	if len(added) != 0 {
+		delta[key] = added
+	}
+	if len(removed) != 0 {
+		delta[removedKey] = removed
+	}
+}
+
+// diffObject returns the fields of current that differ from previous
+func diffObject(previous, current any) map[string]any {
+	var prev, _ = previous.(map[string]any)
+	var cur, _ = current.(map[string]any)
+	var diff = make(map[string]any)
+	for field, value := range cur {
+		if old, ok := prev[field]; !ok || !reflect.DeepEqual(old, value) {
+			diff[field] = value
+		}
+	}
+	return diff
+}
+
+func containsValue(items []any, item any) bool {
+	for _, v := range items {
+		if reflect.DeepEqual(v, item) {
+			return true
+		}
+	}
+	return false
+}
+
+// peerKey returns the key of a peer in /api/v2/sync/torrentPeers
+func peerKey(ip string, port int) string {
+	if strings.Contains(ip, ":") {
+		return "[" + ip + "]:" + strconv.Itoa(port)
+	}
+	return ip + ":" + strconv.Itoa(port)
+}
@@ -0,0 +1,1096 @@
+package qbittest
+
+import (
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/base32"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/xuthus5/qbittorrent-client-go/qbittorrent/internal/bencode"
+)
+
+// Torrent is a torrent stored in the fake server, fields left empty get sensible defaults
+type Torrent struct {
+	Hash                     string
+	Name                     string
+	State                    string
+	Category                 string
+	Tags                     []string
+	SavePath                 string
+	DownloadPath             string
+	Size                     int64
+	Progress                 float64
+	DlSpeed                  int64
+	UpSpeed                  int64
+	Downloaded               int64
+	Uploaded                 int64
+	Eta                      int64
+	Priority                 int
+	DlLimit                  int64
+	UpLimit                  int64
+	RatioLimit               float64
+	SeedingTimeLimit         int64
+	InactiveSeedingTimeLimit int64
+	AutoTMM                  bool
+	ForceStart               bool
+	SuperSeeding             bool
+	SequentialDownload       bool
+	FirstLastPiecePrio       bool
+	NumSeeds                 int
+	NumLeechs                int
+	AddedOn                  int64
+	CompletionOn             int64
+	TimeActive               int64
+	SeedingTime              int64
+	Comment                  string
+	Private                  bool
+	Trackers                 []*Tracker
+	WebSeeds                 []string
+	Files                    []*File
+	Peers                    []*Peer
+	PieceStates              []int
+	PieceHashes              []string
+}
+
+// Tracker is a tracker of a torrent
+type Tracker struct {
+	URL    string
+	Status int
+	Msg    string
+}
+
+// File is a file of a torrent
+type File struct {
+	Name         string
+	Size         int64
+	Progress     float64
+	Priority     int
+	Availability float64
+}
+
+// Peer is a peer connected to a torrent
+type Peer struct {
+	IP         string
+	Port       int
+	Client     string
+	Connection string
+	Country    string
+	Flags      string
+	Progress   float64
+	DlSpeed    int64
+	UpSpeed    int64
+	Downloaded int64
+	Uploaded   int64
+}
+
+// Category is a torrent category
+type Category struct {
+	Name     string
+	SavePath string
+}
+
+// AddTorrent add or replace a torrent, the category and tags of the torrent are created if missing
+func (s *Server) AddTorrent(t *Torrent) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.addTorrent(t)
+}
+
+// Torrent returns a copy of the torrent with the given hash
+func (s *Server) Torrent(hash string) (*Torrent, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	t, ok := s.torrents[strings.ToLower(hash)]
+	if !ok {
+		return nil, false
+	}
+	return t.clone(), true
+}
+
+// Torrents returns copies of all torrents ordered by hash
+func (s *Server) Torrents() []*Torrent {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var torrents = make([]*Torrent, 0, len(s.torrents))
+	for _, hash := range sortedKeys(s.torrents) {
+		torrents = append(torrents, s.torrents[hash].clone())
+	}
+	return torrents
+}
+
+// UpdateTorrent modify a torrent in place, returns false if the torrent does not exist
+func (s *Server) UpdateTorrent(hash string, update func(t *Torrent)) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	t, ok := s.torrents[strings.ToLower(hash)]
+	if ok {
+		update(t)
+	}
+	return ok
+}
+
+// RemoveTorrent remove a torrent
+func (s *Server) RemoveTorrent(hash string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.torrents, strings.ToLower(hash))
+}
+
+func (s *Server) addTorrent(t *Torrent) {
+	t.Hash = strings.ToLower(t.Hash)
+	if t.Name == "" {
+		t.Name = t.Hash
+	}
+	if t.State == "" {
+		t.State = "downloading"
+		if t.Progress >= 1 {
+			t.State = "uploading"
+		}
+	}
+	if t.SavePath == "" {
+		t.SavePath = s.defaultSavePath()
+	}
+	if t.AddedOn == 0 {
+		t.AddedOn = time.Now().Unix()
+	}
+	if t.RatioLimit == 0 {
+		t.RatioLimit = -2
+	}
+	if t.SeedingTimeLimit == 0 {
+		t.SeedingTimeLimit = -2
+	}
+	if t.InactiveSeedingTimeLimit == 0 {
+		t.InactiveSeedingTimeLimit = -2
+	}
+	// torrents fetching metadata have no files yet
+	if len(t.Files) == 0 && t.State != "metaDL" && t.State != "forcedMetaDL" {
+		t.Files = []*File{{Name: t.Name, Size: t.Size, Progress: t.Progress, Priority: 1}}
+	}
+	if t.Category != "" {
+		if _, ok := s.categories[t.Category]; !ok {
+			s.categories[t.Category] = &Category{Name: t.Category}
+		}
+	}
+	for _, tag := range t.Tags {
+		s.tags[tag] = struct{}{}
+	}
+	if t.Priority == 0 && s.queueingEnabled() {
+		t.Priority = len(s.torrents) + 1
+	}
+	s.torrents[t.Hash] = t
+}
+
+func (t *Torrent) clone() *Torrent {
+	var c = *t
+	c.Tags = append([]string(nil), t.Tags...)
+	c.WebSeeds = append([]string(nil), t.WebSeeds...)
+	c.PieceStates = append([]int(nil), t.PieceStates...)
+	c.PieceHashes = append([]string(nil), t.PieceHashes...)
+	c.Trackers = make([]*Tracker, len(t.Trackers))
+	for i, tracker := range t.Trackers {
+		var v = *tracker
+		c.Trackers[i] = &v
+	}
+	c.Files = make([]*File, len(t.Files))
+	for i, file := range t.Files {
+		var v = *file
+		c.Files[i] = &v
+	}
+	c.Peers = make([]*Peer, len(t.Peers))
+	for i, peer := range t.Peers {
+		var v = *peer
+		c.Peers[i] = &v
+	}
+	return &c
+}
+
+func (t *Torrent) paused() bool {
+	return strings.HasPrefix(t.State, "paused") || strings.HasPrefix(t.State, "stopped")
+}
+
+func (t *Torrent) completed() bool {
+	return t.Progress >= 1
+}
+
+// tracker returns the working tracker, the server clears it while the torrent is paused or queued
+func (t *Torrent) tracker() string {
+	if t.paused() || strings.HasPrefix(t.State, "queued") {
+		return ""
+	}
+	for _, tracker := range t.Trackers {
+		if tracker.Status == 2 {
+			return tracker.URL
+		}
+	}
+	return ""
+}
+
+func (t *Torrent) magnetURI() string {
+	var query = url.Values{}
+	query.Set("dn", t.Name)
+	for _, tracker := range t.Trackers {
+		query.Add("tr", tracker.URL)
+	}
+	return "magnet:?xt=urn:btih:" + t.Hash + "&" + query.Encode()
+}
+
+// info serialize the torrent the same way as /api/v2/torrents/info
+func (t *Torrent) info() map[string]any {
+	var ratio float64
+	if t.Downloaded > 0 {
+		ratio = float64(t.Uploaded) / float64(t.Downloaded)
+	}
+	var completed = int64(float64(t.Size) * t.Progress)
+	var tags = append([]string(nil), t.Tags...)
+	sort.Strings(tags)
+	return map[string]any{
+		"added_on":                    t.AddedOn,
+		"amount_left":                 t.Size - completed,
+		"auto_tmm":                    t.AutoTMM,
+		"availability":                float64(t.NumSeeds),
+		"category":                    t.Category,
+		"completed":                   completed,
+		"completion_on":               t.CompletionOn,
+		"content_path":                path.Join(t.SavePath, t.Name),
+		"dl_limit":                    t.DlLimit,
+		"dlspeed":                     t.DlSpeed,
+		"download_path":               t.DownloadPath,
+		"downloaded":                  t.Downloaded,
+		"downloaded_session":          t.Downloaded,
+		"eta":                         t.Eta,
+		"f_l_piece_prio":              t.FirstLastPiecePrio,
+		"force_start":                 t.ForceStart,
+		"hash":                        t.Hash,
+		"inactive_seeding_time_limit": t.InactiveSeedingTimeLimit,
+		"infohash_v1":                 t.Hash,
+		"infohash_v2":                 "",
+		"last_activity":               t.AddedOn,
+		"magnet_uri":                  t.magnetURI(),
+		"max_inactive_seeding_time":   -1,
+		"max_ratio":                   -1,
+		"max_seeding_time":            -1,
+		"name":                        t.Name,
+		"num_complete":                t.NumSeeds,
+		"num_incomplete":              t.NumLeechs,
+		"num_leechs":                  t.NumLeechs,
+		"num_seeds":                   t.NumSeeds,
+		"priority":                    t.Priority,
+		"progress":                    t.Progress,
+		"ratio":                       ratio,
+		"ratio_limit":                 t.RatioLimit,
+		"save_path":                   t.SavePath,
+		"seeding_time":                t.SeedingTime,
+		"seeding_time_limit":          t.SeedingTimeLimit,
+		"seen_complete":               t.CompletionOn,
+		"seq_dl":                      t.SequentialDownload,
+		"size":                        t.Size,
+		"state":                       t.State,
+		"super_seeding":               t.SuperSeeding,
+		"tags":                        strings.Join(tags, ", "),
+		"time_active":                 t.TimeActive,
+		"total_size":                  t.Size,
+		"tracker":                     t.tracker(),
+		"trackers_count":              len(t.Trackers),
+		"up_limit":                    t.UpLimit,
+		"uploaded":                    t.Uploaded,
+		"uploaded_session":            t.Uploaded,
+		"upspeed":                     t.UpSpeed,
+	}
+}
+
+func (p *Peer) key() string {
+	return peerKey(p.IP, p.Port)
+}
+
+func (p *Peer) info() map[string]any {
+	return map[string]any{
+		"client":         p.Client,
+		"connection":     p.Connection,
+		"country":        p.Country,
+		"country_code":   "",
+		"dl_speed":       p.DlSpeed,
+		"downloaded":     p.Downloaded,
+		"files":          "",
+		"flags":          p.Flags,
+		"flags_desc":     "",
+		"ip":             p.IP,
+		"peer_id_client": p.Client,
+		"port":           p.Port,
+		"progress":       p.Progress,
+		"relevance":      p.Progress,
+		"up_speed":       p.UpSpeed,
+		"uploaded":       p.Uploaded,
+	}
+}
+
+func (s *Server) queueingEnabled() bool {
+	enabled, _ := s.preferences["queueing_enabled"].(bool)
+	return enabled
+}
+
+func (s *Server) defaultSavePath() string {
+	savePath, _ := s.preferences["save_path"].(string)
+	return savePath
+}
+
+// selectTorrents returns the torrents of the hashes parameter, "all" selects every torrent
+func (s *Server) selectTorrents(r *http.Request) []*Torrent {
+	var hashes = r.FormValue("hashes")
+	var torrents []*Torrent
+	if hashes == "all" {
+		for _, hash := range sortedKeys(s.torrents) {
+			torrents = append(torrents, s.torrents[hash])
+		}
+		return torrents
+	}
+	for _, hash := range splitList(hashes, "|") {
+		if t, ok := s.torrents[strings.ToLower(hash)]; ok {
+			torrents = append(torrents, t)
+		}
+	}
+	return torrents
+}
+
+// lookupTorrent returns the torrent of the hash parameter, responds 404 if missing
+func (s *Server) lookupTorrent(w http.ResponseWriter, r *http.Request) (*Torrent, bool) {
+	t, ok := s.torrents[strings.ToLower(r.FormValue("hash"))]
+	if !ok {
+		writeError(w, http.StatusNotFound, "Not Found")
+	}
+	return t, ok
+}
+
+// forEachTorrent register an endpoint that applies fn to every selected torrent
+func (s *Server) forEachTorrent(endpoint string, fn func(r *http.Request, t *Torrent)) {
+	s.handle(endpoint, func(w http.ResponseWriter, r *http.Request) {
+		for _, t := range s.selectTorrents(r) {
+			fn(r, t)
+		}
+	})
+}
+
+func (s *Server) registerTorrents() {
+	s.handle("torrents/info", s.handleTorrentsInfo)
+	s.handle("torrents/properties", func(w http.ResponseWriter, r *http.Request) {
+		t, ok := s.lookupTorrent(w, r)
+		if !ok {
+			return
+		}
+		info := t.info()
+		writeJSON(w, map[string]any{
+			"addition_date":        t.AddedOn,
+			"comment":              t.Comment,
+			"completion_date":      t.CompletionOn,
+			"dl_limit":             t.DlLimit,
+			"dl_speed":             t.DlSpeed,
+			"download_path":        t.DownloadPath,
+			"eta":                  t.Eta,
+			"hash":                 t.Hash,
+			"infohash_v1":          t.Hash,
+			"is_private":           t.Private,
+			"name":                 t.Name,
+			"peers":                t.NumLeechs,
+			"pieces_num":           len(t.PieceHashes),
+			"save_path":            t.SavePath,
+			"seeding_time":         t.SeedingTime,
+			"seeds":                t.NumSeeds,
+			"share_ratio":          info["ratio"],
+			"time_elapsed":         t.TimeActive,
+			"total_downloaded":     t.Downloaded,
+			"total_size":           t.Size,
+			"total_uploaded":       t.Uploaded,
+			"up_limit":             t.UpLimit,
+			"up_speed":             t.UpSpeed,
+			"nb_connections":       len(t.Peers),
+			"piece_size":           0,
+			"pieces_have":          0,
+			"reannounce":           0,
+			"total_wasted":         0,
+			"created_by":           "",
+			"creation_date":        t.AddedOn,
+			"last_seen":            t.CompletionOn,
+			"peers_total":          t.NumLeechs,
+			"seeds_total":          t.NumSeeds,
+			"dl_speed_avg":         t.DlSpeed,
+			"up_speed_avg":         t.UpSpeed,
+			"infohash_v2":          "",
+			"nb_connections_limit": 100,
+		})
+	})
+	s.handle("torrents/trackers", func(w http.ResponseWriter, r *http.Request) {
+		t, ok := s.lookupTorrent(w, r)
+		if !ok {
+			return
+		}
+		var trackers = make([]map[string]any, 0, len(t.Trackers))
+		for _, tracker := range t.Trackers {
+			trackers = append(trackers, map[string]any{
+				"url":    tracker.URL,
+				"status": tracker.Status,
+				"msg":    tracker.Msg,
+				"tier":   0,
+			})
+		}
+		writeJSON(w, trackers)
+	})
+	s.handle("torrents/webseeds", func(w http.ResponseWriter, r *http.Request) {
+		t, ok := s.lookupTorrent(w, r)
+		if !ok {
+			return
+		}
+		var seeds = make([]map[string]any, 0, len(t.WebSeeds))
+		for _, seed := range t.WebSeeds {
+			seeds = append(seeds, map[string]any{"url": seed})
+		}
+		writeJSON(w, seeds)
+	})
+	s.handle("torrents/files", func(w http.ResponseWriter, r *http.Request) {
+		t, ok := s.lookupTorrent(w, r)
+		if !ok {
+			return
+		}
+		var indexes = make(map[int]bool)
+		for _, index := range splitList(r.FormValue("indexes"), "|") {
+			i, _ := strconv.Atoi(index)
+			indexes[i] = true
+		}
+		var files = make([]map[string]any, 0, len(t.Files))
+		for i, file := range t.Files {
+			if len(indexes) != 0 && !indexes[i] {
+				continue
+			}
+			files = append(files, map[string]any{
+				"index":        i,
+				"name":         file.Name,
+				"size":         file.Size,
+				"progress":     file.Progress,
+				"priority":     file.Priority,
+				"is_seed":      file.Progress >= 1,
+				"availability": file.Availability,
+				"piece_range":  []int{0, 0},
+			})
+		}
+		writeJSON(w, files)
+	})
+	s.handle("torrents/pieceStates", func(w http.ResponseWriter, r *http.Request) {
+		if t, ok := s.lookupTorrent(w, r); ok {
+			writeJSON(w, append([]int{}, t.PieceStates...))
+		}
+	})
+	s.handle("torrents/pieceHashes", func(w http.ResponseWriter, r *http.Request) {
+		if t, ok := s.lookupTorrent(w, r); ok {
+			writeJSON(w, append([]string{}, t.PieceHashes...))
+		}
+	})
+	s.forEachTorrent("torrents/pause", func(r *http.Request, t *Torrent) {
+		t.State = "pausedDL"
+		if t.completed() {
+			t.State = "pausedUP"
+		}
+		t.DlSpeed, t.UpSpeed = 0, 0
+	})
+	s.forEachTorrent("torrents/resume", func(r *http.Request, t *Torrent) {
+		t.State = "downloading"
+		if t.completed() {
+			t.State = "uploading"
+		}
+	})
+	s.forEachTorrent("torrents/stop", func(r *http.Request, t *Torrent) {
+		t.State = "stoppedDL"
+		if t.completed() {
+			t.State = "stoppedUP"
+		}
+		t.DlSpeed, t.UpSpeed = 0, 0
+	})
+	s.forEachTorrent("torrents/start", func(r *http.Request, t *Torrent) {
+		t.State = "downloading"
+		if t.completed() {
+			t.State = "uploading"
+		}
+	})
+	s.handle("torrents/delete", func(w http.ResponseWriter, r *http.Request) {
+		for _, t := range s.selectTorrents(r) {
+			delete(s.torrents, t.Hash)
+		}
+	})
+	s.forEachTorrent("torrents/recheck", func(r *http.Request, t *Torrent) {})
+	s.forEachTorrent("torrents/reannounce", func(r *http.Request, t *Torrent) {})
+	s.handle("torrents/add", s.handleTorrentsAdd)
+	s.handle("torrents/addTrackers", func(w http.ResponseWriter, r *http.Request) {
+		t, ok := s.lookupTorrent(w, r)
+		if !ok {
+			return
+		}
+		for _, u := range splitList(r.FormValue("urls"), "\n") {
+			if t.trackerIndex(u) < 0 {
+				t.Trackers = append(t.Trackers, &Tracker{URL: u, Status: 1})
+			}
+		}
+	})
+	s.handle("torrents/editTracker", func(w http.ResponseWriter, r *http.Request) {
+		t, ok := s.lookupTorrent(w, r)
+		if !ok {
+			return
+		}
+		var origUrl, newUrl = r.FormValue("origUrl"), r.FormValue("newUrl")
+		if _, err := url.ParseRequestURI(newUrl); err != nil {
+			writeError(w, http.StatusBadRequest, "New tracker URL is invalid")
+			return
+		}
+		var index = t.trackerIndex(origUrl)
+		if index < 0 || t.trackerIndex(newUrl) >= 0 {
+			writeError(w, http.StatusConflict, "Tracker conflict")
+			return
+		}
+		t.Trackers[index].URL = newUrl
+	})
+	s.handle("torrents/removeTrackers", func(w http.ResponseWriter, r *http.Request) {
+		t, ok := s.lookupTorrent(w, r)
+		if !ok {
+			return
+		}
+		var removed bool
+		for _, u := range splitList(r.FormValue("urls"), "|") {
+			if index := t.trackerIndex(u); index >= 0 {
+				t.Trackers = append(t.Trackers[:index], t.Trackers[index+1:]...)
+				removed = true
+			}
+		}
+		if !removed {
+			writeError(w, http.StatusConflict, "No trackers were removed")
+		}
+	})
+	s.handle("torrents/addPeers", func(w http.ResponseWriter, r *http.Request) {
+		for _, t := range s.selectTorrents(r) {
+			for _, peer := range splitList(r.FormValue("peers"), "|") {
+				host, port, _ := strings.Cut(peer, ":")
+				p, _ := strconv.Atoi(port)
+				t.Peers = append(t.Peers, &Peer{IP: host, Port: p, Connection: "BT"})
+			}
+		}
+	})
+	s.handlePriority("torrents/increasePrio", func(t *Torrent, max int) int { return t.Priority - 1 })
+	s.handlePriority("torrents/decreasePrio", func(t *Torrent, max int) int { return t.Priority + 1 })
+	s.handlePriority("torrents/topPrio", func(t *Torrent, max int) int { return 1 })
+	s.handlePriority("torrents/bottomPrio", func(t *Torrent, max int) int { return max })
+	s.handle("torrents/filePrio", func(w http.ResponseWriter, r *http.Request) {
+		t, ok := s.lookupTorrent(w, r)
+		if !ok {
+			return
+		}
+		priority, err := strconv.Atoi(r.FormValue("priority"))
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "Priority is invalid")
+			return
+		}
+		for _, id := range splitList(r.FormValue("id"), "|") {
+			index, err := strconv.Atoi(id)
+			if err != nil || index < 0 || index >= len(t.Files) {
+				writeError(w, http.StatusConflict, "File IDs are invalid")
+				return
+			}
+			t.Files[index].Priority = priority
+		}
+	})
+	s.handle("torrents/downloadLimit", func(w http.ResponseWriter, r *http.Request) {
+		var limits = make(map[string]int64)
+		for _, t := range s.selectTorrents(r) {
+			limits[t.Hash] = t.DlLimit
+		}
+		writeJSON(w, limits)
+	})
+	s.forEachTorrent("torrents/setDownloadLimit", func(r *http.Request, t *Torrent) {
+		t.DlLimit, _ = strconv.ParseInt(r.FormValue("limit"), 10, 64)
+	})
+	s.forEachTorrent("torrents/setShareLimits", func(r *http.Request, t *Torrent) {
+		t.RatioLimit, _ = strconv.ParseFloat(r.FormValue("ratioLimit"), 64)
+		t.SeedingTimeLimit, _ = strconv.ParseInt(r.FormValue("seedingTimeLimit"), 10, 64)
+		t.InactiveSeedingTimeLimit, _ = strconv.ParseInt(r.FormValue("inactiveSeedingTimeLimit"), 10, 64)
+	})
+	s.handle("torrents/uploadLimit", func(w http.ResponseWriter, r *http.Request) {
+		var limits = make(map[string]int64)
+		for _, t := range s.selectTorrents(r) {
+			limits[t.Hash] = t.UpLimit
+		}
+		writeJSON(w, limits)
+	})
+	s.forEachTorrent("torrents/setUploadLimit", func(r *http.Request, t *Torrent) {
+		t.UpLimit, _ = strconv.ParseInt(r.FormValue("limit"), 10, 64)
+	})
+	s.handle("torrents/setLocation", func(w http.ResponseWriter, r *http.Request) {
+		var location = r.FormValue("location")
+		if location == "" {
+			writeError(w, http.StatusBadRequest, "Save path cannot be empty")
+			return
+		}
+		for _, t := range s.selectTorrents(r) {
+			t.SavePath = location
+			t.AutoTMM = false
+		}
+	})
+	s.handle("torrents/rename", func(w http.ResponseWriter, r *http.Request) {
+		t, ok := s.lookupTorrent(w, r)
+		if !ok {
+			return
+		}
+		var name = strings.TrimSpace(r.FormValue("name"))
+		if name == "" {
+			writeError(w, http.StatusConflict, "Incorrect torrent name")
+			return
+		}
+		t.Name = name
+	})
+	s.handle("torrents/setCategory", func(w http.ResponseWriter, r *http.Request) {
+		var category = r.FormValue("category")
+		if _, ok := s.categories[category]; category != "" && !ok {
+			writeError(w, http.StatusConflict, "Incorrect category name")
+			return
+		}
+		for _, t := range s.selectTorrents(r) {
+			t.Category = category
+		}
+	})
+	s.handle("torrents/categories", func(w http.ResponseWriter, r *http.Request) {
+		var categories = make(map[string]any, len(s.categories))
+		for name, category := range s.categories {
+			categories[name] = map[string]any{"name": category.Name, "savePath": category.SavePath}
+		}
+		writeJSON(w, categories)
+	})
+	s.handle("torrents/createCategory", func(w http.ResponseWriter, r *http.Request) {
+		var name = strings.TrimSpace(r.FormValue("category"))
+		if name == "" {
+			writeError(w, http.StatusBadRequest, "Category cannot be empty")
+			return
+		}
+		if _, ok := s.categories[name]; ok {
+			writeError(w, http.StatusConflict, "Unable to create category")
+			return
+		}
+		s.categories[name] = &Category{Name: name, SavePath: r.FormValue("savePath")}
+	})
+	s.handle("torrents/editCategory", func(w http.ResponseWriter, r *http.Request) {
+		category, ok := s.categories[r.FormValue("category")]
+		if !ok {
+			writeError(w, http.StatusConflict, "Unable to edit category")
+			return
+		}
+		category.SavePath = r.FormValue("savePath")
+	})
+	s.handle("torrents/removeCategories", func(w http.ResponseWriter, r *http.Request) {
+		for _, name := range splitList(r.FormValue("categories"), "\n") {
+			delete(s.categories, name)
+			for _, t := range s.torrents {
+				if t.Category == name {
+					t.Category = ""
+				}
+			}
+		}
+	})
+	s.forEachTorrent("torrents/addTags", func(r *http.Request, t *Torrent) {
+		for _, tag := range splitList(r.FormValue("tags"), ",") {
+			s.tags[tag] = struct{}{}
+			if !slices.Contains(t.Tags, tag) {
+				t.Tags = append(t.Tags, tag)
+			}
+		}
+	})
+	s.forEachTorrent("torrents/setTags", func(r *http.Request, t *Torrent) {
+		var tags = splitList(r.FormValue("tags"), ",")
+		for _, tag := range tags {
+			s.tags[tag] = struct{}{}
+		}
+		t.Tags = tags
+	})
+	s.forEachTorrent("torrents/removeTags", func(r *http.Request, t *Torrent) {
+		var tags = splitList(r.FormValue("tags"), ",")
+		if len(tags) == 0 {
+			t.Tags = nil
+			return
+		}
+		t.Tags = removeAll(t.Tags, tags)
+	})
+	s.handle("torrents/tags", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, sortedKeys(s.tags))
+	})
+	s.handle("torrents/createTags", func(w http.ResponseWriter, r *http.Request) {
+		for _, tag := range splitList(r.FormValue("tags"), ",") {
+			s.tags[tag] = struct{}{}
+		}
+	})
+	s.handle("torrents/deleteTags", func(w http.ResponseWriter, r *http.Request) {
+		var tags = splitList(r.FormValue("tags"), ",")
+		for _, tag := range tags {
+			delete(s.tags, tag)
+		}
+		for _, t := range s.torrents {
+			t.Tags = removeAll(t.Tags, tags)
+		}
+	})
+	s.forEachTorrent("torrents/setAutoManagement", func(r *http.Request, t *Torrent) {
+		t.AutoTMM = r.FormValue("enable") == "true"
+	})
+	s.forEachTorrent("torrents/toggleSequentialDownload", func(r *http.Request, t *Torrent) {
+		t.SequentialDownload = !t.SequentialDownload
+	})
+	s.forEachTorrent("torrents/toggleFirstLastPiecePrio", func(r *http.Request, t *Torrent) {
+		t.FirstLastPiecePrio = !t.FirstLastPiecePrio
+	})
+	s.forEachTorrent("torrents/setForceStart", func(r *http.Request, t *Torrent) {
+		t.ForceStart = r.FormValue("value") == "true"
+	})
+	s.forEachTorrent("torrents/setSuperSeeding", func(r *http.Request, t *Torrent) {
+		t.SuperSeeding = r.FormValue("value") == "true"
+	})
+	s.handle("torrents/renameFile", func(w http.ResponseWriter, r *http.Request) {
+		t, ok := s.lookupTorrent(w, r)
+		if !ok {
+			return
+		}
+		var oldPath, newPath = r.FormValue("oldPath"), r.FormValue("newPath")
+		for _, file := range t.Files {
+			if file.Name == oldPath {
+				file.Name = newPath
+				return
+			}
+		}
+		writeError(w, http.StatusConflict, "Invalid file path")
+	})
+	s.handle("torrents/renameFolder", func(w http.ResponseWriter, r *http.Request) {
+		t, ok := s.lookupTorrent(w, r)
+		if !ok {
+			return
+		}
+		var oldPath, newPath = r.FormValue("oldPath") + "/", r.FormValue("newPath") + "/"
+		var renamed bool
+		for _, file := range t.Files {
+			if strings.HasPrefix(file.Name, oldPath) {
+				file.Name = newPath + strings.TrimPrefix(file.Name, oldPath)
+				renamed = true
+			}
+		}
+		if !renamed {
+			writeError(w, http.StatusConflict, "Invalid folder path")
+		}
+	})
+}
+
+func (s *Server) handleTorrentsInfo(w http.ResponseWriter, r *http.Request) {
+	var hashes map[string]bool
+	if r.Form.Has("hashes") {
+		hashes = make(map[string]bool)
+		for _, hash := range splitList(r.FormValue("hashes"), "|") {
+			hashes[strings.ToLower(hash)] = true
+		}
+	}
+	var filter = r.FormValue("filter")
+	var stopStart = compareVersions(s.webAPIVersion, "2.11.0") >= 0
+	var torrents []map[string]any
+	for _, hash := range sortedKeys(s.torrents) {
+		t := s.torrents[hash]
+		if hashes != nil && !hashes[hash] {
+			continue
+		}
+		if !matchFilter(t, filter, stopStart) {
+			continue
+		}
+		if r.Form.Has("category") && t.Category != r.FormValue("category") {
+			continue
+		}
+		if r.Form.Has("tag") {
+			if tag := r.FormValue("tag"); (tag == "" && len(t.Tags) != 0) || (tag != "" && !slices.Contains(t.Tags, tag)) {
+				continue
+			}
+		}
+		torrents = append(torrents, t.info())
+	}
+
+	if key := r.FormValue("sort"); key != "" {
+		sort.SliceStable(torrents, func(i, j int) bool {
+			return less(torrents[i][key], torrents[j][key])
+		})
+	}
+	if r.FormValue("reverse") == "true" {
+		for i, j := 0, len(torrents)-1; i < j; i, j = i+1, j-1 {
+			torrents[i], torrents[j] = torrents[j], torrents[i]
+		}
+	}
+	if offset, _ := strconv.Atoi(r.FormValue("offset")); offset != 0 {
+		if offset < 0 {
+			offset = max(len(torrents)+offset, 0)
+		}
+		torrents = torrents[min(offset, len(torrents)):]
+	}
+	if limit, _ := strconv.Atoi(r.FormValue("limit")); limit > 0 && limit < len(torrents) {
+		torrents = torrents[:limit]
+	}
+	if torrents == nil {
+		torrents = []map[string]any{}
+	}
+	writeJSON(w, torrents)
+}
+
+func (s *Server) handleTorrentsAdd(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseMultipartForm(32 << 20); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	var added []*Torrent
+	for _, u := range splitList(r.FormValue("urls"), "\n") {
+		added = append(added, torrentFromURL(u))
+	}
+	if r.MultipartForm != nil {
+		for _, header := range r.MultipartForm.File["torrents"] {
+			file, err := header.Open()
+			if err != nil {
+				writeError(w, http.StatusBadRequest, err.Error())
+				return
+			}
+			data, err := io.ReadAll(file)
+			_ = file.Close()
+			t, ok := torrentFromFile(data)
+			if err != nil || !ok {
+				writeError(w, http.StatusUnsupportedMediaType, "Fails.")
+				return
+			}
+			added = append(added, t)
+		}
+		// parts without a filename are parsed as values by mime/multipart
+		for _, data := range r.MultipartForm.Value["torrents"] {
+			t, ok := torrentFromFile([]byte(data))
+			if !ok {
+				writeError(w, http.StatusUnsupportedMediaType, "Fails.")
+				return
+			}
+			added = append(added, t)
+		}
+	}
+	if len(added) == 0 {
+		writeError(w, http.StatusUnsupportedMediaType, "Fails.")
+		return
+	}
+
+	var category = r.FormValue("category")
+	if _, ok := s.categories[category]; category != "" && !ok {
+		s.categories[category] = &Category{Name: category}
+	}
+	var count int
+	for _, t := range added {
+		if _, ok := s.torrents[t.Hash]; ok {
+			continue
+		}
+		count++
+		t.Category = category
+		t.Tags = splitList(r.FormValue("tags"), ",")
+		t.SavePath = r.FormValue("savepath")
+		if name := r.FormValue("rename"); name != "" {
+			t.Name = name
+		}
+		// webapi 2.11.0 renamed paused to stopped, the old name is ignored by newer servers
+		if compareVersions(s.webAPIVersion, "2.11.0") >= 0 {
+			if r.FormValue("stopped") == "true" {
+				t.State = "stoppedDL"
+			}
+		} else if r.FormValue("paused") == "true" {
+			t.State = "pausedDL"
+		}
+		t.ForceStart = r.FormValue("forced") == "true"
+		if r.FormValue("useDownloadPath") == "true" {
+			t.DownloadPath = r.FormValue("downloadPath")
+		}
+		t.AutoTMM = r.FormValue("autoTMM") == "true"
+		t.SequentialDownload = r.FormValue("sequentialDownload") == "true"
+		t.FirstLastPiecePrio = r.FormValue("firstLastPiecePrio") == "true"
+		t.UpLimit, _ = strconv.ParseInt(r.FormValue("upLimit"), 10, 64)
+		t.DlLimit, _ = strconv.ParseInt(r.FormValue("dlLimit"), 10, 64)
+		if ratio, err := strconv.ParseFloat(r.FormValue("ratioLimit"), 64); err == nil {
+			t.RatioLimit = ratio
+		}
+		if limit, err := strconv.ParseInt(r.FormValue("seedingTimeLimit"), 10, 64); err == nil {
+			t.SeedingTimeLimit = limit
+		}
+		if limit, err := strconv.ParseInt(r.FormValue("inactiveSeedingTimeLimit"), 10, 64); err == nil {
+			t.InactiveSeedingTimeLimit = limit
+		}
+		s.addTorrent(t)
+	}
+	// the server answers 200 with Fails. when no torrent was added, such as only duplicates
+	if count == 0 {
+		writeText(w, "Fails.")
+		return
+	}
+	writeText(w, "Ok.")
+}
+
+// torrentFromURL create a torrent from magnet link or url, magnet links keep their info hash
+func torrentFromURL(u string) *Torrent {
+	var t = &Torrent{State: "metaDL"}
+	if parsed, err := url.Parse(u); err == nil && parsed.Scheme == "magnet" {
+		var query = parsed.Query()
+		t.Name = query.Get("dn")
+		for _, xt := range query["xt"] {
+			if hash, ok := strings.CutPrefix(xt, "urn:btih:"); ok {
+				t.Hash = normalizeInfoHash(hash)
+			}
+			// v2-only torrents are identified by the truncated sha256 infohash
+			if hash, ok := strings.CutPrefix(xt, "urn:btmh:1220"); ok && t.Hash == "" && len(hash) == 64 {
+				t.Hash = strings.ToLower(hash[:40])
+			}
+		}
+		for _, tr := range query["tr"] {
+			t.Trackers = append(t.Trackers, &Tracker{URL: tr, Status: 1})
+		}
+	}
+	if t.Hash == "" {
+		sum := sha1.Sum([]byte(u))
+		t.Hash = hex.EncodeToString(sum[:])
+	}
+	return t
+}
+
+// torrentFromFile create a torrent from uploaded torrent file, the hash is the infohash of the info
+// dictionary, truncated to 20 bytes for v2-only torrents. ok is false if the file is not a torrent
+func torrentFromFile(data []byte) (*Torrent, bool) {
+	decoded, err := bencode.Decode(data)
+	if err != nil {
+		return nil, false
+	}
+	rawInfo, ok, err := bencode.RawValue(data, "info")
+	if err != nil || !ok {
+		return nil, false
+	}
+	info, _ := decoded.(map[string]any)["info"].(map[string]any)
+	var t = &Torrent{State: "downloading"}
+	t.Name, _ = info["name"].(string)
+	if _, v1 := info["pieces"]; v1 {
+		sum := sha1.Sum(rawInfo)
+		t.Hash = hex.EncodeToString(sum[:])
+	} else {
+		sum := sha256.Sum256(rawInfo)
+		t.Hash = hex.EncodeToString(sum[:sha1.Size])
+	}
+	if length, ok := info["length"].(int64); ok {
+		t.Size = length
+	}
+	return t, true
+}
+
+// normalizeInfoHash convert a base32 info hash to lower case hex
+func normalizeInfoHash(hash string) string {
+	if len(hash) == 32 {
+		if data, err := base32.StdEncoding.DecodeString(strings.ToUpper(hash)); err == nil {
+			return hex.EncodeToString(data)
+		}
+	}
+	return strings.ToLower(hash)
+}
+
+func (s *Server) handlePriority(endpoint string, priority func(t *Torrent, max int) int) {
+	s.handle(endpoint, func(w http.ResponseWriter, r *http.Request) {
+		if !s.queueingEnabled() {
+			writeError(w, http.StatusConflict, "Torrent queueing must be enabled")
+			return
+		}
+		for _, t := range s.selectTorrents(r) {
+			t.Priority = min(max(priority(t, len(s.torrents)), 1), len(s.torrents))
+		}
+	})
+}
+
+func (t *Torrent) trackerIndex(u string) int {
+	for i, tracker := range t.Trackers {
+		if tracker.URL == u {
+			return i
+		}
+	}
+	return -1
+}
+
+// matchFilter reports whether the torrent matches the state filter of /api/v2/torrents/info, webapi
+// 2.11.0 renamed paused and resumed to stopped and running, unknown filters match all torrents
+func matchFilter(t *Torrent, filter string, stopStart bool) bool {
+	var active = t.DlSpeed > 0 || t.UpSpeed > 0
+	switch filter {
+	case "paused", "resumed":
+		if stopStart {
+			return true
+		}
+	case "stopped", "running":
+		if !stopStart {
+			return true
+		}
+	}
+	switch filter {
+	case "downloading":
+		return !t.completed() && !t.paused()
+	case "seeding":
+		return t.completed() && !t.paused()
+	case "completed":
+		return t.completed()
+	case "paused", "stopped":
+		return t.paused()
+	case "resumed", "running":
+		return !t.paused()
+	case "active":
+		return active
+	case "inactive":
+		return !active
+	case "stalled":
+		return strings.HasPrefix(t.State, "stalled")
+	case "stalled_uploading":
+		return t.State == "stalledUP"
+	case "stalled_downloading":
+		return t.State == "stalledDL"
+	case "checking":
+		return strings.HasPrefix(t.State, "checking")
+	case "moving":
+		return t.State == "moving"
+	case "errored":
+		return t.State == "error" || t.State == "missingFiles"
+	}
+	return true
+}
+
+// less compare two json values of the same field
+func less(a, b any) bool {
+	switch av := a.(type) {
+	case string:
+		bv, _ := b.(string)
+		return av < bv
+	case bool:
+		bv, _ := b.(bool)
+		return !av && bv
+	}
+	return toFloat(a) < toFloat(b)
+}
+
+func toFloat(v any) float64 {
+	switch n := v.(type) {
+	case int:
+		return float64(n)
+	case int64:
+		return float64(n)
+	case float64:
+		return n
+	}
+	return 0
+}
+
+func removeAll(items, remove []string) []string {
+	var result []string
+	for _, item := range items {
+		if !slices.Contains(remove, item) {
+			result = append(result, item)
+		}
+	}
+	return result
+}
@@ -0,0 +1,108 @@
+package qbittorrent
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"net/http"
+	"net/url"
+	"slices"
+	"time"
+)
+
+// RetryPolicy controls how failed requests are retried
+type RetryPolicy struct {
+	// MaxAttempts maximum number of attempts including the first one, default 3
+	MaxAttempts int
+	// BaseDelay delay before the first retry, doubled after every attempt, default 200 milliseconds
+	BaseDelay time.Duration
+	// MaxDelay upper bound of the delay between two attempts, default 5 seconds
+	MaxDelay time.Duration
+	// Jitter randomizes every delay by ±Jitter, in range [0, 1], e.g. 0.2 means ±20%
+	Jitter float64
+	// StatusCodes response status codes that should be retried, default 502, 503 and 504
+	StatusCodes []int
+	// Methods HTTP methods that are idempotent enough to retry, default GET and HEAD.
+	// most of the qBittorrent write api are POST, add it explicitly if the retry is acceptable
+	Methods []string
+}
+
+var (
+	defaultRetryStatusCodes = []int{http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout}
+	defaultRetryMethods     = []string{http.MethodGet, http.MethodHead}
+)
+
+func (p *RetryPolicy) maxAttempts() int {
+	if p.MaxAttempts <= 0 {
+		return 3
+	}
+	return p.MaxAttempts
+}
+
+// delay returns the wait duration before the given retry, retry starts from 1
+func (p *RetryPolicy) delay(retry int) time.Duration {
+	var base, maxDelay = p.BaseDelay, p.MaxDelay
+	if base <= 0 {
+		base = time.Millisecond * 200
+	}
+	if maxDelay <= 0 {
+		maxDelay = time.Second * 5
+	}
+	var d = base
+	for i := 1; i < retry && d < maxDelay; i++ {
+		d *= 2
+	}
+	d = min(d, maxDelay)
+	if p.Jitter > 0 {
+		d = time.Duration(float64(d) * (1 + min(p.Jitter, 1)*(2*rand.Float64()-1)))
+	}
+	return d
+}
+
+func (p *RetryPolicy) retryMethod(method string) bool {
+	if len(p.Methods) == 0 {
+		return slices.Contains(defaultRetryMethods, method)
+	}
+	return slices.Contains(p.Methods, method)
+}
+
+func (p *RetryPolicy) retryStatus(code int) bool {
+	if len(p.StatusCodes) == 0 {
+		return slices.Contains(defaultRetryStatusCodes, code)
+	}
+	return slices.Contains(p.StatusCodes, code)
+}
+
+// retryable reports whether the result of an attempt should be retried
+func (p *RetryPolicy) retryable(ctx context.Context, method string, result *responseResult, err error) bool {
+	if ctx.Err() != nil || !p.retryMethod(method) {
+		return false
+	}
+	if err != nil {
+		// only transport errors such as connection reset or timeout are retried
+		var urlErr *url.Error
+		return errors.As(err, &urlErr)
+	}
+	return p.retryStatus(result.code)
+}
+
+// sendWithRetry send request and retry on transient failures according to Config.Retry
+func (c *client) sendWithRetry(ctx context.Context, data *requestData, body []byte) (*responseResult, error) {
+	var policy = c.config.Retry
+	if policy == nil {
+		return c.sendRequest(ctx, data, body)
+	}
+	for attempt := 1; ; attempt++ {
+		result, err := c.sendRequest(ctx, data, body)
+		if attempt >= policy.maxAttempts() || !policy.retryable(ctx, data.method, result, err) {
+			return result, err
+		}
+		timer := time.NewTimer(policy.delay(attempt))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package qbittorrent
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRetryPolicy_Delay(t *testing.T) {
+	policy := &RetryPolicy{BaseDelay: time.Millisecond * 100, MaxDelay: time.Millisecond * 350}
+	for retry, expected := range []time.Duration{100, 200, 350, 350} {
+		if d := policy.delay(retry + 1); d != expected*time.Millisecond {
+			t.Fatalf("retry %d: expected %s, got %s", retry+1, expected*time.Millisecond, d)
+		}
+	}
+
+	policy.Jitter = 0.5
+	for i := 0; i < 100; i++ {
+		if d := policy.delay(1); d < time.Millisecond*50 || d > time.Millisecond*150 {
+			t.Fatalf("delay %s out of jitter range", d)
+		}
+	}
+}
+
+func TestClient_Retry(t *testing.T) {
+	var attempts atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v2/auth/login":
+			_, _ = w.Write([]byte("Ok."))
+		case "/api/v2/app/setPreferences":
+			if attempts.Add(1) < 3 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			if r.FormValue("json") != `{"file_log_age":1}` {
+				w.WriteHeader(http.StatusBadRequest)
+			}
+		}
+	}))
+	defer server.Close()
+
+	cli, err := NewClient(&Config{
+		Address:  server.URL,
+		Username: "admin",
+		Password: "adminadmin",
+		Retry: &RetryPolicy{
+			BaseDelay: time.Millisecond,
+			Methods:   []string{http.MethodPost},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := cli.Application().SetPreferences(&Preferences{FileLogAge: 1}); err != nil {
+		t.Fatal(err)
+	}
+	if attempts.Load() != 3 {
+		t.Fatalf("expected 3 attempts, got %d", attempts.Load())
+	}
+}
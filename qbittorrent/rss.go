@@ -1,10 +1,10 @@
 package qbittorrent
 
 import (
-	"errors"
-	"fmt"
+	"context"
 	"net/http"
 	"net/url"
+	"strconv"
 	"strings"
 
 	"github.com/bytedance/sonic"
@@ -13,29 +13,53 @@ import (
 type RSS interface {
 	// AddFolder create new folder for rss, full path of added folder such as "The Pirate Bay\Top100"
 	AddFolder(path string) error
+	// AddFolderContext is the context-aware version of AddFolder
+	AddFolderContext(ctx context.Context, path string) error
 	// AddFeed add feed
 	AddFeed(*RssAddFeedOption) error
+	// AddFeedContext is the context-aware version of AddFeed
+	AddFeedContext(ctx context.Context, opt *RssAddFeedOption) error
 	// RemoveItem remove folder or feed
 	RemoveItem(path string) error
+	// RemoveItemContext is the context-aware version of RemoveItem
+	RemoveItemContext(ctx context.Context, path string) error
 	// MoveItem move or rename folder or feed
 	MoveItem(srcPath, destPath string) error
+	// MoveItemContext is the context-aware version of MoveItem
+	MoveItemContext(ctx context.Context, srcPath, destPath string) error
 	// GetItems list all items, if withData is true, will return all data
 	GetItems(withData bool) (map[string]interface{}, error)
+	// GetItemsContext is the context-aware version of GetItems
+	GetItemsContext(ctx context.Context, withData bool) (map[string]interface{}, error)
 	// MarkAsRead if articleId is provided only the article is marked as read otherwise the whole feed
 	// is going to be marked as read.
 	MarkAsRead(*RssMarkAsReadOption) error
+	// MarkAsReadContext is the context-aware version of MarkAsRead
+	MarkAsReadContext(ctx context.Context, opt *RssMarkAsReadOption) error
 	// RefreshItem refresh folder or feed
 	RefreshItem(itemPath string) error
+	// RefreshItemContext is the context-aware version of RefreshItem
+	RefreshItemContext(ctx context.Context, itemPath string) error
 	// SetAutoDownloadingRule set auto-downloading rule
 	SetAutoDownloadingRule(ruleName string, ruleDef *RssAutoDownloadingRuleDef) error
+	// SetAutoDownloadingRuleContext is the context-aware version of SetAutoDownloadingRule
+	SetAutoDownloadingRuleContext(ctx context.Context, ruleName string, ruleDef *RssAutoDownloadingRuleDef) error
 	// RenameAutoDownloadingRule rename auto-downloading rule
 	RenameAutoDownloadingRule(ruleName, newRuleName string) error
+	// RenameAutoDownloadingRuleContext is the context-aware version of RenameAutoDownloadingRule
+	RenameAutoDownloadingRuleContext(ctx context.Context, ruleName, newRuleName string) error
 	// RemoveAutoDownloadingRule remove auto-downloading rule
 	RemoveAutoDownloadingRule(ruleName string) error
+	// RemoveAutoDownloadingRuleContext is the context-aware version of RemoveAutoDownloadingRule
+	RemoveAutoDownloadingRuleContext(ctx context.Context, ruleName string) error
 	// GetAllAutoDownloadingRules get all auto-downloading rules
 	GetAllAutoDownloadingRules() (map[string]*RssAutoDownloadingRuleDef, error)
+	// GetAllAutoDownloadingRulesContext is the context-aware version of GetAllAutoDownloadingRules
+	GetAllAutoDownloadingRulesContext(ctx context.Context) (map[string]*RssAutoDownloadingRuleDef, error)
 	// GetAllArticlesMatchingRule get all articles matching a rule
 	GetAllArticlesMatchingRule(ruleName string) (map[string][]string, error)
+	// GetAllArticlesMatchingRuleContext is the context-aware version of GetAllArticlesMatchingRule
+	GetAllArticlesMatchingRuleContext(ctx context.Context, ruleName string) (map[string][]string, error)
 }
 
 type RssAddFeedOption struct {
@@ -87,10 +111,14 @@ type RssAutoDownloadingRuleDef struct {
 }
 
 func (c *client) AddFolder(path string) error {
+	return c.AddFolderContext(context.Background(), path)
+}
+
+func (c *client) AddFolderContext(ctx context.Context, path string) error {
 	var formData = url.Values{}
 	formData.Add("path", path)
-	var apiUrl = fmt.Sprintf("%s/api/v2/rss/addFolder", c.config.Address)
-	result, err := c.doRequest(&requestData{
+	var apiUrl = c.apiURL("rss/addFolder", nil)
+	result, err := c.doRequest(ctx, &requestData{
 		url:    apiUrl,
 		method: http.MethodPost,
 		body:   strings.NewReader(formData.Encode()),
@@ -100,19 +128,23 @@ func (c *client) AddFolder(path string) error {
 	}
 
 	if result.code != 200 {
-		return errors.New("add rss folder failed: " + string(result.body))
+		return newAPIError("add rss folder failed", result)
 	}
 	return nil
 }
 
 func (c *client) AddFeed(opt *RssAddFeedOption) error {
+	return c.AddFeedContext(context.Background(), opt)
+}
+
+func (c *client) AddFeedContext(ctx context.Context, opt *RssAddFeedOption) error {
 	var formData = url.Values{}
 	err := encoder.Encode(opt, formData)
 	if err != nil {
 		return err
 	}
-	var apiUrl = fmt.Sprintf("%s/api/v2/rss/addFolder", c.config.Address)
-	result, err := c.doRequest(&requestData{
+	var apiUrl = c.apiURL("rss/addFeed", nil)
+	result, err := c.doRequest(ctx, &requestData{
 		url:    apiUrl,
 		method: http.MethodPost,
 		body:   strings.NewReader(formData.Encode()),
@@ -122,16 +154,20 @@ func (c *client) AddFeed(opt *RssAddFeedOption) error {
 	}
 
 	if result.code != 200 {
-		return errors.New("add rss feed failed: " + string(result.body))
+		return newAPIError("add rss feed failed", result)
 	}
 	return nil
 }
 
 func (c *client) RemoveItem(path string) error {
+	return c.RemoveItemContext(context.Background(), path)
+}
+
+func (c *client) RemoveItemContext(ctx context.Context, path string) error {
 	var formData = url.Values{}
 	formData.Add("path", path)
-	var apiUrl = fmt.Sprintf("%s/api/v2/rss/removeItem", c.config.Address)
-	result, err := c.doRequest(&requestData{
+	var apiUrl = c.apiURL("rss/removeItem", nil)
+	result, err := c.doRequest(ctx, &requestData{
 		url:    apiUrl,
 		method: http.MethodPost,
 		body:   strings.NewReader(formData.Encode()),
@@ -141,17 +177,21 @@ func (c *client) RemoveItem(path string) error {
 	}
 
 	if result.code != 200 {
-		return errors.New("remove rss item failed: " + string(result.body))
+		return newAPIError("remove rss item failed", result)
 	}
 	return nil
 }
 
 func (c *client) MoveItem(srcPath, destPath string) error {
+	return c.MoveItemContext(context.Background(), srcPath, destPath)
+}
+
+func (c *client) MoveItemContext(ctx context.Context, srcPath, destPath string) error {
 	var formData = url.Values{}
 	formData.Add("itemPath", srcPath)
 	formData.Add("destPath", destPath)
-	var apiUrl = fmt.Sprintf("%s/api/v2/rss/moveItem", c.config.Address)
-	result, err := c.doRequest(&requestData{
+	var apiUrl = c.apiURL("rss/moveItem", nil)
+	result, err := c.doRequest(ctx, &requestData{
 		url:    apiUrl,
 		method: http.MethodPost,
 		body:   strings.NewReader(formData.Encode()),
@@ -161,14 +201,18 @@ func (c *client) MoveItem(srcPath, destPath string) error {
 	}
 
 	if result.code != 200 {
-		return errors.New("move rss item failed: " + string(result.body))
+		return newAPIError("move rss item failed", result)
 	}
 	return nil
 }
 
 func (c *client) GetItems(withData bool) (map[string]interface{}, error) {
-	var apiUrl = fmt.Sprintf("%s/api/v2/rss/items?withData=%t", c.config.Address, withData)
-	result, err := c.doRequest(&requestData{
+	return c.GetItemsContext(context.Background(), withData)
+}
+
+func (c *client) GetItemsContext(ctx context.Context, withData bool) (map[string]interface{}, error) {
+	var apiUrl = c.apiURL("rss/items", url.Values{"withData": {strconv.FormatBool(withData)}})
+	result, err := c.doRequest(ctx, &requestData{
 		url:    apiUrl,
 		method: http.MethodGet,
 	})
@@ -177,7 +221,7 @@ func (c *client) GetItems(withData bool) (map[string]interface{}, error) {
 	}
 
 	if result.code != 200 {
-		return nil, errors.New("get rss items failed: " + string(result.body))
+		return nil, newAPIError("get rss items failed", result)
 	}
 	var data = make(map[string]interface{})
 	err = sonic.Unmarshal(result.body, &data)
@@ -185,13 +229,17 @@ func (c *client) GetItems(withData bool) (map[string]interface{}, error) {
 }
 
 func (c *client) MarkAsRead(opt *RssMarkAsReadOption) error {
+	return c.MarkAsReadContext(context.Background(), opt)
+}
+
+func (c *client) MarkAsReadContext(ctx context.Context, opt *RssMarkAsReadOption) error {
 	var formData = url.Values{}
 	err := encoder.Encode(opt, formData)
 	if err != nil {
 		return err
 	}
-	var apiUrl = fmt.Sprintf("%s/api/v2/rss/markAsRead", c.config.Address)
-	result, err := c.doRequest(&requestData{
+	var apiUrl = c.apiURL("rss/markAsRead", nil)
+	result, err := c.doRequest(ctx, &requestData{
 		url:    apiUrl,
 		method: http.MethodPost,
 		body:   strings.NewReader(formData.Encode()),
@@ -201,16 +249,20 @@ func (c *client) MarkAsRead(opt *RssMarkAsReadOption) error {
 	}
 
 	if result.code != 200 {
-		return errors.New("mark as read rss item failed: " + string(result.body))
+		return newAPIError("mark as read rss item failed", result)
 	}
 	return nil
 }
 
 func (c *client) RefreshItem(itemPath string) error {
+	return c.RefreshItemContext(context.Background(), itemPath)
+}
+
+func (c *client) RefreshItemContext(ctx context.Context, itemPath string) error {
 	var formData = url.Values{}
 	formData.Add("itemPath", itemPath)
-	var apiUrl = fmt.Sprintf("%s/api/v2/rss/refreshItem", c.config.Address)
-	result, err := c.doRequest(&requestData{
+	var apiUrl = c.apiURL("rss/refreshItem", nil)
+	result, err := c.doRequest(ctx, &requestData{
 		url:    apiUrl,
 		method: http.MethodPost,
 		body:   strings.NewReader(formData.Encode()),
@@ -220,12 +272,16 @@ func (c *client) RefreshItem(itemPath string) error {
 	}
 
 	if result.code != 200 {
-		return errors.New("refresh rss item failed: " + string(result.body))
+		return newAPIError("refresh rss item failed", result)
 	}
 	return nil
 }
 
 func (c *client) SetAutoDownloadingRule(ruleName string, ruleDef *RssAutoDownloadingRuleDef) error {
+	return c.SetAutoDownloadingRuleContext(context.Background(), ruleName, ruleDef)
+}
+
+func (c *client) SetAutoDownloadingRuleContext(ctx context.Context, ruleName string, ruleDef *RssAutoDownloadingRuleDef) error {
 	var formData = url.Values{}
 	formData.Add("ruleName", ruleName)
 	ruleDefBytes, err := sonic.Marshal(ruleDef)
@@ -233,8 +289,8 @@ func (c *client) SetAutoDownloadingRule(ruleName string, ruleDef *RssAutoDownloa
 		return err
 	}
 	formData.Add("ruleDef", string(ruleDefBytes))
-	var apiUrl = fmt.Sprintf("%s/api/v2/rss/setRule", c.config.Address)
-	result, err := c.doRequest(&requestData{
+	var apiUrl = c.apiURL("rss/setRule", nil)
+	result, err := c.doRequest(ctx, &requestData{
 		url:    apiUrl,
 		method: http.MethodPost,
 		body:   strings.NewReader(formData.Encode()),
@@ -244,17 +300,21 @@ func (c *client) SetAutoDownloadingRule(ruleName string, ruleDef *RssAutoDownloa
 	}
 
 	if result.code != 200 {
-		return errors.New("set auto downloading rule failed: " + string(result.body))
+		return newAPIError("set auto downloading rule failed", result)
 	}
 	return nil
 }
 
 func (c *client) RenameAutoDownloadingRule(ruleName, newRuleName string) error {
+	return c.RenameAutoDownloadingRuleContext(context.Background(), ruleName, newRuleName)
+}
+
+func (c *client) RenameAutoDownloadingRuleContext(ctx context.Context, ruleName, newRuleName string) error {
 	var formData = url.Values{}
 	formData.Add("ruleName", ruleName)
 	formData.Add("newRuleName", newRuleName)
-	var apiUrl = fmt.Sprintf("%s/api/v2/rss/renameRule", c.config.Address)
-	result, err := c.doRequest(&requestData{
+	var apiUrl = c.apiURL("rss/renameRule", nil)
+	result, err := c.doRequest(ctx, &requestData{
 		url:    apiUrl,
 		method: http.MethodPost,
 		body:   strings.NewReader(formData.Encode()),
@@ -264,16 +324,20 @@ func (c *client) RenameAutoDownloadingRule(ruleName, newRuleName string) error {
 	}
 
 	if result.code != 200 {
-		return errors.New("rename auto downloading rule failed: " + string(result.body))
+		return newAPIError("rename auto downloading rule failed", result)
 	}
 	return nil
 }
 
 func (c *client) RemoveAutoDownloadingRule(ruleName string) error {
+	return c.RemoveAutoDownloadingRuleContext(context.Background(), ruleName)
+}
+
+func (c *client) RemoveAutoDownloadingRuleContext(ctx context.Context, ruleName string) error {
 	var formData = url.Values{}
 	formData.Add("ruleName", ruleName)
-	var apiUrl = fmt.Sprintf("%s/api/v2/rss/removeRule", c.config.Address)
-	result, err := c.doRequest(&requestData{
+	var apiUrl = c.apiURL("rss/removeRule", nil)
+	result, err := c.doRequest(ctx, &requestData{
 		url:    apiUrl,
 		method: http.MethodPost,
 		body:   strings.NewReader(formData.Encode()),
@@ -283,21 +347,25 @@ func (c *client) RemoveAutoDownloadingRule(ruleName string) error {
 	}
 
 	if result.code != 200 {
-		return errors.New("remove auto downloading rule failed: " + string(result.body))
+		return newAPIError("remove auto downloading rule failed", result)
 	}
 	return nil
 }
 
 func (c *client) GetAllAutoDownloadingRules() (map[string]*RssAutoDownloadingRuleDef, error) {
-	var apiUrl = fmt.Sprintf("%s/api/v2/rss/matchingArticles", c.config.Address)
-	result, err := c.doRequest(&requestData{
+	return c.GetAllAutoDownloadingRulesContext(context.Background())
+}
+
+func (c *client) GetAllAutoDownloadingRulesContext(ctx context.Context) (map[string]*RssAutoDownloadingRuleDef, error) {
+	var apiUrl = c.apiURL("rss/rules", nil)
+	result, err := c.doRequest(ctx, &requestData{
 		url: apiUrl,
 	})
 	if err != nil {
 		return nil, err
 	}
 	if result.code != 200 {
-		return nil, errors.New("get rss rules failed: " + string(result.body))
+		return nil, newAPIError("get rss rules failed", result)
 	}
 	var data = make(map[string]*RssAutoDownloadingRuleDef)
 	err = sonic.Unmarshal(result.body, &data)
@@ -305,17 +373,21 @@ func (c *client) GetAllAutoDownloadingRules() (map[string]*RssAutoDownloadingRul
 }
 
 func (c *client) GetAllArticlesMatchingRule(ruleName string) (map[string][]string, error) {
+	return c.GetAllArticlesMatchingRuleContext(context.Background(), ruleName)
+}
+
+func (c *client) GetAllArticlesMatchingRuleContext(ctx context.Context, ruleName string) (map[string][]string, error) {
 	var formData = url.Values{}
 	formData.Add("ruleName", ruleName)
-	var apiUrl = fmt.Sprintf("%s/api/v2/rss/matchingArticles?%s", c.config.Address, formData.Encode())
-	result, err := c.doRequest(&requestData{
+	var apiUrl = c.apiURL("rss/matchingArticles", formData)
+	result, err := c.doRequest(ctx, &requestData{
 		url: apiUrl,
 	})
 	if err != nil {
 		return nil, err
 	}
 	if result.code != 200 {
-		return nil, errors.New("get rss rule match articles failed: " + string(result.body))
+		return nil, newAPIError("get rss rule match articles failed", result)
 	}
 	var data = make(map[string][]string)
 	err = sonic.Unmarshal(result.body, &data)
@@ -1,64 +1,373 @@
 package qbittorrent
 
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/bytedance/sonic"
+)
+
+type SearchOption struct {
+	Pattern  string   // pattern to search for, e.g. "Ubuntu 18.04"
+	Plugins  []string // plugins to use for searching, empty means all, "enabled" means all enabled plugins
+	Category string   // categories to limit your search to, empty means all
+}
+
+type SearchJob struct {
+	Id     int    `json:"id"`               // id of the search job
+	Status string `json:"status,omitempty"` // current status of the search job, Running or Stopped
+	Total  int    `json:"total"`            // total number of results, if the status is Running this number may continue to increase
+}
+
+type SearchResult struct {
+	DescrLink  string `json:"descrLink"`  // url of the torrent's description page
+	FileName   string `json:"fileName"`   // name of the file
+	FileSize   int64  `json:"fileSize"`   // size of the file in bytes
+	FileUrl    string `json:"fileUrl"`    // torrent download link, usually either .torrent file or magnet link
+	NbLeechers int    `json:"nbLeechers"` // number of leechers
+	NbSeeders  int    `json:"nbSeeders"`  // number of seeders
+	SiteUrl    string `json:"siteUrl"`    // url of the torrent site
+}
+
+type SearchResults struct {
+	Results []*SearchResult `json:"results"` // search results
+	Status  string          `json:"status"`  // current status of the search job, Running or Stopped
+	Total   int             `json:"total"`   // total number of results, if the status is Running this number may continue to increase
+}
+
+type SearchPlugin struct {
+	Enabled             bool                    `json:"enabled"`             // whether the plugin is enabled
+	FullName            string                  `json:"fullName"`            // full name of the plugin
+	Name                string                  `json:"name"`                // short name of the plugin
+	SupportedCategories []*SearchPluginCategory `json:"supportedCategories"` // categories supported by the plugin
+	Url                 string                  `json:"url"`                 // url of the torrent site
+	Version             string                  `json:"version"`             // installed version of the plugin
+}
+
+type SearchPluginCategory struct {
+	Id   string `json:"id"`   // id of the category, used as SearchOption.Category
+	Name string `json:"name"` // localized name of the category
+}
+
+// UnmarshalJSON accept both the category object of qBittorrent 4.3+ and the plain category name of older versions
+func (category *SearchPluginCategory) UnmarshalJSON(data []byte) error {
+	if bytes.HasPrefix(bytes.TrimSpace(data), []byte(`"`)) {
+		var name string
+		if err := sonic.Unmarshal(data, &name); err != nil {
+			return err
+		}
+		category.Id, category.Name = name, name
+		return nil
+	}
+	type plain SearchPluginCategory
+	return sonic.Unmarshal(data, (*plain)(category))
+}
+
 type Search interface {
-	Start()
-	Stop()
-	Status()
-	Results()
-	Delete()
-	Plugins()
-	InstallPlugins()
-	UninstallPlugins()
-	EnableSearchPlugins()
-	UpdateSearchPlugins()
+	// Start start a search job, returns the id of the job
+	Start(opt *SearchOption) (int, error)
+	// StartContext is the context-aware version of Start
+	StartContext(ctx context.Context, opt *SearchOption) (int, error)
+	// Stop stop a running search job
+	Stop(id int) error
+	// StopContext is the context-aware version of Stop
+	StopContext(ctx context.Context, id int) error
+	// Status get the status of a search job, status of all jobs are returned if id is 0
+	Status(id int) ([]*SearchJob, error)
+	// StatusContext is the context-aware version of Status
+	StatusContext(ctx context.Context, id int) ([]*SearchJob, error)
+	// Results get the results of a search job, limit 0 means no limit, a negative offset counts from the end
+	Results(id, limit, offset int) (*SearchResults, error)
+	// ResultsContext is the context-aware version of Results
+	ResultsContext(ctx context.Context, id, limit, offset int) (*SearchResults, error)
+	// Delete delete a search job
+	Delete(id int) error
+	// DeleteContext is the context-aware version of Delete
+	DeleteContext(ctx context.Context, id int) error
+	// Plugins get all installed search plugins
+	Plugins() ([]*SearchPlugin, error)
+	// PluginsContext is the context-aware version of Plugins
+	PluginsContext(ctx context.Context) ([]*SearchPlugin, error)
+	// InstallPlugins install search plugins from urls or file paths
+	InstallPlugins(sources []string) error
+	// InstallPluginsContext is the context-aware version of InstallPlugins
+	InstallPluginsContext(ctx context.Context, sources []string) error
+	// UninstallPlugins uninstall search plugins by name
+	UninstallPlugins(names []string) error
+	// UninstallPluginsContext is the context-aware version of UninstallPlugins
+	UninstallPluginsContext(ctx context.Context, names []string) error
+	// EnableSearchPlugins enable or disable search plugins by name
+	EnableSearchPlugins(names []string, enable bool) error
+	// EnableSearchPluginsContext is the context-aware version of EnableSearchPlugins
+	EnableSearchPluginsContext(ctx context.Context, names []string, enable bool) error
+	// UpdateSearchPlugins update all search plugins
+	UpdateSearchPlugins() error
+	// UpdateSearchPluginsContext is the context-aware version of UpdateSearchPlugins
+	UpdateSearchPluginsContext(ctx context.Context) error
+}
+
+func (c *client) Start(opt *SearchOption) (int, error) {
+	return c.StartContext(context.Background(), opt)
+}
+
+func (c *client) StartContext(ctx context.Context, opt *SearchOption) (int, error) {
+	var plugins = "all"
+	if len(opt.Plugins) != 0 {
+		plugins = strings.Join(opt.Plugins, "|")
+	}
+	var category = opt.Category
+	if category == "" {
+		category = "all"
+	}
+	var formData = url.Values{}
+	formData.Add("pattern", opt.Pattern)
+	formData.Add("plugins", plugins)
+	formData.Add("category", category)
+	var apiUrl = c.apiURL("search/start", nil)
+	result, err := c.doRequest(ctx, &requestData{
+		url:    apiUrl,
+		method: http.MethodPost,
+		body:   strings.NewReader(formData.Encode()),
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	if result.code != 200 {
+		return 0, newAPIError("start search failed", result)
+	}
+
+	var job SearchJob
+	if err := sonic.Unmarshal(result.body, &job); err != nil {
+		return 0, err
+	}
+	return job.Id, nil
+}
+
+func (c *client) Stop(id int) error {
+	return c.StopContext(context.Background(), id)
+}
+
+func (c *client) StopContext(ctx context.Context, id int) error {
+	var formData = url.Values{}
+	formData.Add("id", strconv.Itoa(id))
+	var apiUrl = c.apiURL("search/stop", nil)
+	result, err := c.doRequest(ctx, &requestData{
+		url:    apiUrl,
+		method: http.MethodPost,
+		body:   strings.NewReader(formData.Encode()),
+	})
+	if err != nil {
+		return err
+	}
+
+	if result.code != 200 {
+		return newAPIError("stop search failed", result)
+	}
+	return nil
 }
 
-func (c *client) Start() {
-	//TODO implement me
-	panic("implement me")
+func (c *client) Status(id int) ([]*SearchJob, error) {
+	return c.StatusContext(context.Background(), id)
 }
 
-func (c *client) Stop() {
-	//TODO implement me
-	panic("implement me")
+func (c *client) StatusContext(ctx context.Context, id int) ([]*SearchJob, error) {
+	var formData = url.Values{}
+	if id != 0 {
+		formData.Add("id", strconv.Itoa(id))
+	}
+	var apiUrl = c.apiURL("search/status", nil)
+	result, err := c.doRequest(ctx, &requestData{
+		url:    apiUrl,
+		method: http.MethodPost,
+		body:   strings.NewReader(formData.Encode()),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if result.code != 200 {
+		return nil, newAPIError("get search status failed", result)
+	}
+
+	var jobs []*SearchJob
+	if err := sonic.Unmarshal(result.body, &jobs); err != nil {
+		return nil, err
+	}
+	return jobs, nil
 }
 
-func (c *client) Status() {
-	//TODO implement me
-	panic("implement me")
+func (c *client) Results(id, limit, offset int) (*SearchResults, error) {
+	return c.ResultsContext(context.Background(), id, limit, offset)
 }
 
-func (c *client) Results() {
-	//TODO implement me
-	panic("implement me")
+func (c *client) ResultsContext(ctx context.Context, id, limit, offset int) (*SearchResults, error) {
+	var formData = url.Values{}
+	formData.Add("id", strconv.Itoa(id))
+	if limit != 0 {
+		formData.Add("limit", strconv.Itoa(limit))
+	}
+	if offset != 0 {
+		formData.Add("offset", strconv.Itoa(offset))
+	}
+	var apiUrl = c.apiURL("search/results", nil)
+	result, err := c.doRequest(ctx, &requestData{
+		url:    apiUrl,
+		method: http.MethodPost,
+		body:   strings.NewReader(formData.Encode()),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if result.code != 200 {
+		return nil, newAPIError("get search results failed", result)
+	}
+
+	var results SearchResults
+	if err := sonic.Unmarshal(result.body, &results); err != nil {
+		return nil, err
+	}
+	return &results, nil
 }
 
-func (c *client) Delete() {
-	//TODO implement me
-	panic("implement me")
+func (c *client) Delete(id int) error {
+	return c.DeleteContext(context.Background(), id)
 }
 
-func (c *client) Plugins() {
-	//TODO implement me
-	panic("implement me")
+func (c *client) DeleteContext(ctx context.Context, id int) error {
+	var formData = url.Values{}
+	formData.Add("id", strconv.Itoa(id))
+	var apiUrl = c.apiURL("search/delete", nil)
+	result, err := c.doRequest(ctx, &requestData{
+		url:    apiUrl,
+		method: http.MethodPost,
+		body:   strings.NewReader(formData.Encode()),
+	})
+	if err != nil {
+		return err
+	}
+
+	if result.code != 200 {
+		return newAPIError("delete search failed", result)
+	}
+	return nil
 }
 
-func (c *client) InstallPlugins() {
-	//TODO implement me
-	panic("implement me")
+func (c *client) Plugins() ([]*SearchPlugin, error) {
+	return c.PluginsContext(context.Background())
 }
 
-func (c *client) UninstallPlugins() {
-	//TODO implement me
-	panic("implement me")
+func (c *client) PluginsContext(ctx context.Context) ([]*SearchPlugin, error) {
+	var apiUrl = c.apiURL("search/plugins", nil)
+	result, err := c.doRequest(ctx, &requestData{
+		url: apiUrl,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if result.code != 200 {
+		return nil, newAPIError("get search plugins failed", result)
+	}
+
+	var plugins []*SearchPlugin
+	if err := sonic.Unmarshal(result.body, &plugins); err != nil {
+		return nil, err
+	}
+	return plugins, nil
+}
+
+func (c *client) InstallPlugins(sources []string) error {
+	return c.InstallPluginsContext(context.Background(), sources)
 }
 
-func (c *client) EnableSearchPlugins() {
-	//TODO implement me
-	panic("implement me")
+func (c *client) InstallPluginsContext(ctx context.Context, sources []string) error {
+	var formData = url.Values{}
+	formData.Add("sources", strings.Join(sources, "|"))
+	var apiUrl = c.apiURL("search/installPlugin", nil)
+	result, err := c.doRequest(ctx, &requestData{
+		url:    apiUrl,
+		method: http.MethodPost,
+		body:   strings.NewReader(formData.Encode()),
+	})
+	if err != nil {
+		return err
+	}
+
+	if result.code != 200 {
+		return newAPIError("install search plugins failed", result)
+	}
+	return nil
 }
 
-func (c *client) UpdateSearchPlugins() {
-	//TODO implement me
-	panic("implement me")
+func (c *client) UninstallPlugins(names []string) error {
+	return c.UninstallPluginsContext(context.Background(), names)
+}
+
+func (c *client) UninstallPluginsContext(ctx context.Context, names []string) error {
+	var formData = url.Values{}
+	formData.Add("names", strings.Join(names, "|"))
+	var apiUrl = c.apiURL("search/uninstallPlugin", nil)
+	result, err := c.doRequest(ctx, &requestData{
+		url:    apiUrl,
+		method: http.MethodPost,
+		body:   strings.NewReader(formData.Encode()),
+	})
+	if err != nil {
+		return err
+	}
+
+	if result.code != 200 {
+		return newAPIError("uninstall search plugins failed", result)
+	}
+	return nil
+}
+
+func (c *client) EnableSearchPlugins(names []string, enable bool) error {
+	return c.EnableSearchPluginsContext(context.Background(), names, enable)
+}
+
+func (c *client) EnableSearchPluginsContext(ctx context.Context, names []string, enable bool) error {
+	var formData = url.Values{}
+	formData.Add("names", strings.Join(names, "|"))
+	formData.Add("enable", strconv.FormatBool(enable))
+	var apiUrl = c.apiURL("search/enablePlugin", nil)
+	result, err := c.doRequest(ctx, &requestData{
+		url:    apiUrl,
+		method: http.MethodPost,
+		body:   strings.NewReader(formData.Encode()),
+	})
+	if err != nil {
+		return err
+	}
+
+	if result.code != 200 {
+		return newAPIError("enable search plugins failed", result)
+	}
+	return nil
+}
+
+func (c *client) UpdateSearchPlugins() error {
+	return c.UpdateSearchPluginsContext(context.Background())
+}
+
+func (c *client) UpdateSearchPluginsContext(ctx context.Context) error {
+	var apiUrl = c.apiURL("search/updatePlugins", nil)
+	result, err := c.doRequest(ctx, &requestData{
+		url:    apiUrl,
+		method: http.MethodPost,
+	})
+	if err != nil {
+		return err
+	}
+
+	if result.code != 200 {
+		return newAPIError("update search plugins failed", result)
+	}
+	return nil
 }
@@ -0,0 +1,140 @@
+package qbittorrent
+
+import (
+	"context"
+	"time"
+)
+
+const (
+	defaultSearchPollInterval = time.Second
+	defaultSearchPageSize     = 100
+	// searchCleanupTimeout bounds the stop and delete requests sent after the search finished
+	searchCleanupTimeout = 10 * time.Second
+)
+
+type SearchRunOption struct {
+	SearchOption
+	PollInterval time.Duration // interval between two polls of a running job, default 1s
+	PageSize     int           // number of results fetched per request, default 100
+	MaxResults   int           // stop the search after this many unique results, 0 means no limit
+}
+
+// SearchRun is a search job started by RunSearch
+type SearchRun struct {
+	Id int // id of the search job
+
+	search  Search
+	opt     SearchRunOption
+	results chan *SearchResult
+	done    chan struct{}
+	err     error
+}
+
+// RunSearch start a search job and stream its results until the job finished, ctx is canceled or
+// opt.MaxResults unique results were received. Results are de-duplicated by download link, the
+// results channel is closed when the search ends and the job is always deleted from the server.
+func RunSearch(ctx context.Context, s Search, opt *SearchRunOption) (*SearchRun, error) {
+	id, err := s.StartContext(ctx, &opt.SearchOption)
+	if err != nil {
+		return nil, err
+	}
+	run := &SearchRun{
+		Id:      id,
+		search:  s,
+		opt:     *opt,
+		results: make(chan *SearchResult),
+		done:    make(chan struct{}),
+	}
+	if run.opt.PollInterval <= 0 {
+		run.opt.PollInterval = defaultSearchPollInterval
+	}
+	if run.opt.PageSize <= 0 {
+		run.opt.PageSize = defaultSearchPageSize
+	}
+	go run.run(ctx)
+	return run, nil
+}
+
+// Results returns the channel of the search results, it is closed when the search ends. The
+// channel is unbuffered, the search only makes progress while the results are received
+func (r *SearchRun) Results() <-chan *SearchResult {
+	return r.results
+}
+
+// Wait block until the search ended, returns the error that ended it, or nil if the job finished
+// or the result limit was reached. Wait does not drain Results, the results must be consumed by
+// another goroutine or Wait blocks until ctx of RunSearch is canceled
+func (r *SearchRun) Wait() error {
+	<-r.done
+	return r.err
+}
+
+func (r *SearchRun) run(ctx context.Context) {
+	defer close(r.done)
+	defer close(r.results)
+	r.err = r.poll(ctx)
+	if err := r.cleanup(ctx); r.err == nil {
+		r.err = err
+	}
+}
+
+// poll page through the results until the job stopped and all results were received
+func (r *SearchRun) poll(ctx context.Context) error {
+	var offset, count int
+	var seen = make(map[string]struct{})
+	var timer = time.NewTimer(0)
+	defer timer.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-timer.C:
+		}
+
+		page, err := r.search.ResultsContext(ctx, r.Id, r.opt.PageSize, offset)
+		if err != nil {
+			return err
+		}
+		offset += len(page.Results)
+		for _, result := range page.Results {
+			key := searchResultKey(result)
+			if _, ok := seen[key]; ok {
+				continue
+			}
+			seen[key] = struct{}{}
+			select {
+			case r.results <- result:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+			if count++; r.opt.MaxResults > 0 && count >= r.opt.MaxResults {
+				return nil
+			}
+		}
+		if page.Status == "Stopped" && offset >= page.Total {
+			return nil
+		}
+		// fetch the next page right away if the page was full
+		if len(page.Results) == r.opt.PageSize {
+			timer.Reset(0)
+		} else {
+			timer.Reset(r.opt.PollInterval)
+		}
+	}
+}
+
+// cleanup stop and delete the job, the requests are sent even if ctx was canceled
+func (r *SearchRun) cleanup(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), searchCleanupTimeout)
+	defer cancel()
+	_ = r.search.StopContext(ctx, r.Id)
+	return r.search.DeleteContext(ctx, r.Id)
+}
+
+// searchResultKey identify a result, plugins may return the same torrent more than once
+func searchResultKey(result *SearchResult) string {
+	if result.FileUrl != "" {
+		return result.FileUrl
+	}
+	return result.DescrLink + "\x00" + result.FileName
+}
@@ -0,0 +1,85 @@
+package qbittorrent
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/xuthus5/qbittorrent-client-go/qbittorrent/qbittest"
+)
+
+func TestRunSearch(t *testing.T) {
+	c, server := newTestClient(t)
+	server.AddSearchPlugin(&qbittest.SearchPlugin{Name: "piratebay", Enabled: true})
+	server.AddSearchResults(&qbittest.SearchResult{FileName: "Fedora 40 Workstation", FileUrl: "magnet:?xt=urn:btih:1"})
+	run, err := RunSearch(context.Background(), c.Search(), &SearchRunOption{
+		SearchOption: SearchOption{Pattern: "fedora"},
+		PollInterval: 10 * time.Millisecond,
+		PageSize:     1,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if result := <-run.Results(); result.FileName != "Fedora 40 Workstation" {
+		t.Fatalf("unexpected result: %+v", result)
+	}
+	server.AddSearchResults(
+		&qbittest.SearchResult{FileName: "Fedora 40 Workstation", FileUrl: "magnet:?xt=urn:btih:1"},
+		&qbittest.SearchResult{FileName: "Fedora 40 Server", FileUrl: "magnet:?xt=urn:btih:2"},
+	)
+	if result := <-run.Results(); result.FileName != "Fedora 40 Server" {
+		t.Fatalf("expected duplicated result to be skipped, got %+v", result)
+	}
+	server.FinishSearch(run.Id)
+	if result, ok := <-run.Results(); ok {
+		t.Fatalf("expected results to be closed, got %+v", result)
+	}
+	if err := run.Wait(); err != nil {
+		t.Fatal(err)
+	}
+	if jobs := server.SearchJobs(); len(jobs) != 0 {
+		t.Fatalf("search jobs %v were not deleted", jobs)
+	}
+}
+
+func TestRunSearch_Stop(t *testing.T) {
+	c, server := newTestClient(t)
+	server.AddSearchPlugin(&qbittest.SearchPlugin{Name: "piratebay", Enabled: true})
+	server.AddSearchResults(
+		&qbittest.SearchResult{FileName: "Arch Linux 2024.06.01", FileUrl: "magnet:?xt=urn:btih:3"},
+		&qbittest.SearchResult{FileName: "Arch Linux 2024.07.01", FileUrl: "magnet:?xt=urn:btih:4"},
+	)
+	run, err := RunSearch(context.Background(), c.Search(), &SearchRunOption{
+		SearchOption: SearchOption{Pattern: "arch linux"},
+		PollInterval: 10 * time.Millisecond,
+		MaxResults:   1,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var count int
+	for range run.Results() {
+		count++
+	}
+	if err := run.Wait(); err != nil || count != 1 {
+		t.Fatalf("expected a single result without error, got %d results and %v", count, err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	run, err = RunSearch(ctx, c.Search(), &SearchRunOption{
+		SearchOption: SearchOption{Pattern: "nothing matches"},
+		PollInterval: 10 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	cancel()
+	if err := run.Wait(); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context canceled, got %v", err)
+	}
+	if jobs := server.SearchJobs(); len(jobs) != 0 {
+		t.Fatal("search job was not deleted after cancel")
+	}
+}
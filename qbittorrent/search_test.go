@@ -0,0 +1,65 @@
+package qbittorrent
+
+import (
+	"testing"
+
+	"github.com/bytedance/sonic"
+	"github.com/xuthus5/qbittorrent-client-go/qbittorrent/qbittest"
+)
+
+func TestClient_Search(t *testing.T) {
+	c, server := newTestClient(t)
+	server.AddSearchPlugin(&qbittest.SearchPlugin{Name: "piratebay", FullName: "The Pirate Bay", Enabled: true, Categories: []string{"all", "movies"}})
+	server.AddSearchResults(
+		&qbittest.SearchResult{FileName: "Ubuntu 24.04 Desktop", FileSize: 6 << 30, NbSeeders: 120, Plugin: "piratebay"},
+		&qbittest.SearchResult{FileName: "Ubuntu 24.04 Server", FileSize: 2 << 30, NbSeeders: 80, Plugin: "piratebay"},
+		&qbittest.SearchResult{FileName: "Debian 12", FileSize: 1 << 30, Plugin: "piratebay"},
+	)
+
+	plugins, err := c.Search().Plugins()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(plugins) != 1 || len(plugins[0].SupportedCategories) != 2 {
+		t.Fatalf("unexpected plugins: %+v", plugins)
+	}
+
+	id, err := c.Search().Start(&SearchOption{Pattern: "ubuntu", Plugins: []string{"enabled"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	jobs, err := c.Search().Status(id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(jobs) != 1 || jobs[0].Id != id || jobs[0].Total != 2 {
+		t.Fatalf("unexpected search status: %+v", jobs)
+	}
+	results, err := c.Search().Results(id, 1, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if results.Total != 2 || len(results.Results) != 1 || results.Results[0].FileName != "Ubuntu 24.04 Server" {
+		t.Fatalf("unexpected search results: %+v", results)
+	}
+
+	if err := c.Search().Stop(id); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Search().Delete(id); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.Search().Results(id, 0, 0); !IsNotFound(err) {
+		t.Fatalf("expected not found after delete, got %v", err)
+	}
+}
+
+func TestSearchPluginCategory_UnmarshalJSON(t *testing.T) {
+	var plugin SearchPlugin
+	if err := sonic.Unmarshal([]byte(`{"name":"legacy","supportedCategories":["movies"]}`), &plugin); err != nil {
+		t.Fatal(err)
+	}
+	if len(plugin.SupportedCategories) != 1 || plugin.SupportedCategories[0].Id != "movies" {
+		t.Fatalf("unexpected categories: %+v", plugin.SupportedCategories)
+	}
+}
@@ -0,0 +1,177 @@
+package qbittorrent
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/cookiejar"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/bytedance/sonic"
+)
+
+// Session is an exported login session of the WebUI, it can be saved and used to create a client
+// without login again
+type Session struct {
+	// Address the WebUI address the session belongs to
+	Address string `json:"address"`
+	// Cookies the cookies of the session, including the SID cookie
+	Cookies []*http.Cookie `json:"cookies"`
+	// CreatedAt time of the export
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// SessionStore persists the session of a client between runs
+type SessionStore interface {
+	// LoadSession returns the saved session, nil if there is none. NewClient logs in if it returns an error
+	LoadSession(ctx context.Context) (*Session, error)
+	// SaveSession saves the session, it is called after every successful login
+	SaveSession(ctx context.Context, session *Session) error
+}
+
+// NewClientWithSession create a qBittorrent client from a saved session, Login is only called if the
+// session is rejected by the server
+func NewClientWithSession(cfg *Config, session *Session) (Client, error) {
+	return NewClientWithSessionContext(context.Background(), cfg, session)
+}
+
+// NewClientWithSessionContext is the context-aware version of NewClientWithSession
+func NewClientWithSessionContext(ctx context.Context, cfg *Config, session *Session) (Client, error) {
+	c, err := newClient(cfg)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.resumeSession(ctx, session); err != nil {
+		return nil, err
+	}
+	if cfg.RefreshCookie && cfg.AuthMode == AuthCookie {
+		go c.refreshCookie()
+	}
+	return c, nil
+}
+
+// resumeSession import the session and check it with the webapi version request, login if the
+// session is missing, belongs to another address or is rejected
+func (c *client) resumeSession(ctx context.Context, session *Session) error {
+	if c.config.AuthMode != AuthCookie {
+		return c.authenticate(ctx)
+	}
+	if session == nil || session.Address != c.config.Address {
+		return c.LoginContext(ctx)
+	}
+	if err := c.ImportSession(session); err != nil {
+		return err
+	}
+	_, err := c.webAPIVersion(ctx)
+	if err == nil {
+		return nil
+	}
+	if !IsForbidden(err) {
+		return err
+	}
+	return c.LoginContext(ctx)
+}
+
+func (c *client) ExportSession() (*Session, error) {
+	if c.cookieJar == nil {
+		return nil, ErrNotLogin
+	}
+	cookies := c.cookieJar.Cookies(c.baseURL)
+	if len(cookies) == 0 {
+		return nil, ErrNotLogin
+	}
+	return &Session{Address: c.config.Address, Cookies: cookies, CreatedAt: time.Now()}, nil
+}
+
+func (c *client) ImportSession(session *Session) error {
+	if session == nil || len(session.Cookies) == 0 {
+		return errors.New("session is empty")
+	}
+	if session.Address != c.config.Address {
+		return errors.New("session belongs to " + session.Address)
+	}
+	if c.cookieJar == nil {
+		var err error
+		c.cookieJar, err = cookiejar.New(nil)
+		if err != nil {
+			return err
+		}
+	}
+	c.cookieJar.SetCookies(c.baseURL, session.Cookies)
+	c.loginGeneration.Add(1)
+	c.resetVersion()
+	return nil
+}
+
+// saveSession save the session to Config.SessionStore, a failure is only logged because the login
+// itself succeeded
+func (c *client) saveSession(ctx context.Context) {
+	if c.config.SessionStore == nil {
+		return
+	}
+	session, err := c.ExportSession()
+	if err == nil {
+		err = c.config.SessionStore.SaveSession(ctx, session)
+	}
+	if err != nil && c.config.Logger != nil {
+		c.config.Logger.WarnContext(ctx, "qbittorrent save session failed", "error", err.Error())
+	}
+}
+
+// FileSessionStore is a SessionStore saving the session as a json file
+type FileSessionStore struct {
+	path string
+	mu   sync.Mutex
+}
+
+// NewFileSessionStore create a session store saving to the file at path, the directory is created
+// when the session is saved
+func NewFileSessionStore(path string) *FileSessionStore {
+	return &FileSessionStore{path: path}
+}
+
+func (s *FileSessionStore) LoadSession(_ context.Context) (*Session, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var session Session
+	if err := sonic.Unmarshal(data, &session); err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+// SaveSession write the session to a temporary file and rename it, the file is only readable by the owner
+func (s *FileSessionStore) SaveSession(_ context.Context, session *Session) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	data, err := sonic.Marshal(session)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o700); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), s.path)
+}
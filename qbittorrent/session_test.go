@@ -0,0 +1,170 @@
+package qbittorrent
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+
+	"github.com/xuthus5/qbittorrent-client-go/qbittorrent/qbittest"
+)
+
+// countLogins returns a middleware counting the login requests
+func countLogins(logins *atomic.Int32) Middleware {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			if req.URL.Path == "/api/v2/auth/login" {
+				logins.Add(1)
+			}
+			return next.RoundTrip(req)
+		})
+	}
+}
+
+func TestClient_Session(t *testing.T) {
+	server := qbittest.NewServer()
+	defer server.Close()
+
+	var logins atomic.Int32
+	cfg := &Config{
+		Address:     server.URL,
+		Username:    qbittest.DefaultUsername,
+		Password:    qbittest.DefaultPassword,
+		Middlewares: []Middleware{countLogins(&logins)},
+	}
+	client, err := NewClient(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	session, err := client.Authentication().ExportSession()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if session.Address != server.URL || len(session.Cookies) != 1 || session.Cookies[0].Name != "SID" {
+		t.Fatalf("unexpected session: %+v", session)
+	}
+
+	resumed, err := NewClientWithSession(cfg, session)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := resumed.Application().Version(); err != nil {
+		t.Fatal(err)
+	}
+	if logins.Load() != 1 {
+		t.Fatalf("expected the saved session to be reused, got %d logins", logins.Load())
+	}
+
+	server.ExpireSessions()
+	resumed, err = NewClientWithSession(cfg, session)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if logins.Load() != 2 {
+		t.Fatalf("expected login after the session was rejected, got %d logins", logins.Load())
+	}
+	renewed, err := resumed.Authentication().ExportSession()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if renewed.Cookies[0].Value == session.Cookies[0].Value {
+		t.Fatal("expected a new session")
+	}
+
+	if err := client.Authentication().ImportSession(&Session{Address: "http://other", Cookies: session.Cookies}); err == nil {
+		t.Fatal("expected an error for a session of another address")
+	}
+}
+
+func TestFileSessionStore(t *testing.T) {
+	server := qbittest.NewServer()
+	defer server.Close()
+
+	store := NewFileSessionStore(filepath.Join(t.TempDir(), "qbittorrent", "session.json"))
+	if session, err := store.LoadSession(context.Background()); err != nil || session != nil {
+		t.Fatalf("expected no session, got %v, %v", session, err)
+	}
+
+	var logins atomic.Int32
+	cfg := &Config{
+		Address:      server.URL,
+		Username:     qbittest.DefaultUsername,
+		Password:     qbittest.DefaultPassword,
+		Middlewares:  []Middleware{countLogins(&logins)},
+		SessionStore: store,
+	}
+	for i := 0; i < 3; i++ {
+		client, err := NewClient(cfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := client.Application().Version(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if logins.Load() != 1 {
+		t.Fatalf("expected the stored session to be reused, got %d logins", logins.Load())
+	}
+
+	session, err := store.LoadSession(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if session == nil || session.Address != server.URL || session.Cookies[0].Name != "SID" {
+		t.Fatalf("unexpected session: %+v", session)
+	}
+
+	// the session expired between two runs
+	server.ExpireSessions()
+	client, err := NewClient(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.Application().Version(); err != nil {
+		t.Fatal(err)
+	}
+	if logins.Load() != 2 {
+		t.Fatalf("expected login after the session expired, got %d logins", logins.Load())
+	}
+	saved, err := store.LoadSession(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if saved.Cookies[0].Value == session.Cookies[0].Value {
+		t.Fatal("expected the new session to be saved")
+	}
+}
+
+func TestFileSessionStore_Corrupted(t *testing.T) {
+	server := qbittest.NewServer()
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "session.json")
+	if err := os.WriteFile(path, []byte(`{"Address":"http://127.0.0.1","Cook`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	store := NewFileSessionStore(path)
+	var logins atomic.Int32
+	client, err := NewClient(&Config{
+		Address:      server.URL,
+		Username:     qbittest.DefaultUsername,
+		Password:     qbittest.DefaultPassword,
+		Middlewares:  []Middleware{countLogins(&logins)},
+		SessionStore: store,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.Application().Version(); err != nil {
+		t.Fatal(err)
+	}
+	if logins.Load() != 1 {
+		t.Fatalf("expected login after the corrupted session, got %d logins", logins.Load())
+	}
+	// the corrupted file is replaced by the new session
+	if session, err := store.LoadSession(context.Background()); err != nil || session == nil || session.Address != server.URL {
+		t.Fatalf("expected the new session to be saved, got %v, %v", session, err)
+	}
+}
@@ -1,8 +1,7 @@
 package qbittorrent
 
 import (
-	"errors"
-	"fmt"
+	"context"
 	"net/url"
 	"strconv"
 
@@ -13,47 +12,136 @@ type Sync interface {
 	// MainData get sync main data, rid is Response ID. if not provided, will be assumed.
 	// if the given is different from the one of last server reply, will be (see the server reply details for more info)
 	MainData(rid int) (*SyncMainData, error)
+	// MainDataContext is the context-aware version of MainData
+	MainDataContext(ctx context.Context, rid int) (*SyncMainData, error)
 	// TorrentPeersData get sync torrent peer data, hash is torrent hash, rid is response id
 	TorrentPeersData(hash string, rid int) (*SyncTorrentPeers, error)
+	// TorrentPeersDataContext is the context-aware version of TorrentPeersData
+	TorrentPeersDataContext(ctx context.Context, hash string, rid int) (*SyncTorrentPeers, error)
 }
 
+// SyncMainData is a response of /api/v2/sync/maindata. Unless FullUpdate is true it only
+// contains what changed since the given rid, fields that did not change are nil.
 type SyncMainData struct {
-	Rid         int                        `json:"rid,omitempty"`
-	FullUpdate  bool                       `json:"full_update,omitempty"`
-	ServerState ServerState                `json:"server_state,omitempty"`
-	Torrents    map[string]SyncTorrentInfo `json:"torrents,omitempty"`
+	Rid               int                        `json:"rid,omitempty"`                // response id
+	FullUpdate        bool                       `json:"full_update,omitempty"`        // whether the response contains all data
+	Torrents          map[string]SyncTorrentInfo `json:"torrents,omitempty"`           // property: torrent hash, value: changed fields
+	TorrentsRemoved   []string                   `json:"torrents_removed,omitempty"`   // hashes of removed torrents
+	Categories        map[string]SyncCategory    `json:"categories,omitempty"`         // property: category name, value: changed fields
+	CategoriesRemoved []string                   `json:"categories_removed,omitempty"` // names of removed categories
+	Tags              []string                   `json:"tags,omitempty"`               // added tags
+	TagsRemoved       []string                   `json:"tags_removed,omitempty"`       // removed tags
+	Trackers          map[string][]string        `json:"trackers,omitempty"`           // property: tracker url, value: hashes of the torrents using it
+	TrackersRemoved   []string                   `json:"trackers_removed,omitempty"`   // urls of removed trackers
+	ServerState       ServerState                `json:"server_state,omitempty"`       // changed fields of the global transfer info
+
+	// raw response body, used by Syncer to merge the fields that are not modelled
+	raw []byte
+}
+
+type SyncCategory struct {
+	Name         *string `json:"name,omitempty"`
+	SavePath     *string `json:"savePath,omitempty"`
+	DownloadPath *string `json:"download_path,omitempty"`
 }
 
 type ServerState struct {
-	AllTimeDl          int64  `json:"alltime_dl,omitempty"`
-	AllTimeUl          int64  `json:"alltime_ul,omitempty"`
-	AverageTimeQueue   int    `json:"average_time_queue,omitempty"`
-	DlInfoData         int64  `json:"dl_info_data,omitempty"`
-	DlInfoSpeed        int    `json:"dl_info_speed,omitempty"`
-	QueuedIoJobs       int    `json:"queued_io_jobs,omitempty"`
-	TotalBuffersSize   int    `json:"total_buffers_size,omitempty"`
-	UpInfoData         int64  `json:"up_info_data,omitempty"`
-	UpInfoSpeed        int    `json:"up_info_speed,omitempty"`
-	WriteCacheOverload string `json:"write_cache_overload,omitempty"`
+	AllTimeDl             *int64  `json:"alltime_dl,omitempty"`
+	AllTimeUl             *int64  `json:"alltime_ul,omitempty"`
+	AverageTimeQueue      *int    `json:"average_time_queue,omitempty"`
+	ConnectionStatus      *string `json:"connection_status,omitempty"` // connected, firewalled or disconnected
+	DhtNodes              *int    `json:"dht_nodes,omitempty"`
+	DlInfoData            *int64  `json:"dl_info_data,omitempty"`
+	DlInfoSpeed           *int    `json:"dl_info_speed,omitempty"`
+	DlRateLimit           *int    `json:"dl_rate_limit,omitempty"`
+	FreeSpaceOnDisk       *int64  `json:"free_space_on_disk,omitempty"`
+	GlobalRatio           *string `json:"global_ratio,omitempty"`
+	LastExternalAddressV4 *string `json:"last_external_address_v4,omitempty"`
+	LastExternalAddressV6 *string `json:"last_external_address_v6,omitempty"`
+	QueuedIoJobs          *int    `json:"queued_io_jobs,omitempty"`
+	Queueing              *bool   `json:"queueing,omitempty"`
+	ReadCacheHits         *string `json:"read_cache_hits,omitempty"`
+	ReadCacheOverload     *string `json:"read_cache_overload,omitempty"`
+	RefreshInterval       *int    `json:"refresh_interval,omitempty"`
+	TotalBuffersSize      *int    `json:"total_buffers_size,omitempty"`
+	TotalPeerConnections  *int    `json:"total_peer_connections,omitempty"`
+	TotalQueuedSize       *int64  `json:"total_queued_size,omitempty"`
+	TotalWastedSession    *int64  `json:"total_wasted_session,omitempty"`
+	UpInfoData            *int64  `json:"up_info_data,omitempty"`
+	UpInfoSpeed           *int    `json:"up_info_speed,omitempty"`
+	UpRateLimit           *int    `json:"up_rate_limit,omitempty"`
+	UseAltSpeedLimits     *bool   `json:"use_alt_speed_limits,omitempty"`
+	UseSubcategories      *bool   `json:"use_subcategories,omitempty"`
+	WriteCacheOverload    *string `json:"write_cache_overload,omitempty"`
 }
 
 type SyncTorrentInfo struct {
-	AmountLeft        int64   `json:"amount_left,omitempty"`
-	Completed         int     `json:"completed,omitempty"`
-	DlSpeed           int     `json:"dlspeed,omitempty"`
-	Downloaded        int     `json:"downloaded,omitempty"`
-	DownloadedSession int     `json:"downloaded_session,omitempty"`
-	Eta               int     `json:"eta,omitempty"`
-	Progress          float64 `json:"progress,omitempty"`
-	SeenComplete      int     `json:"seen_complete,omitempty"`
-	TimeActive        int     `json:"time_active,omitempty"`
+	AddedOn                  *int          `json:"added_on,omitempty"`
+	AmountLeft               *int64        `json:"amount_left,omitempty"`
+	AutoTmm                  *bool         `json:"auto_tmm,omitempty"`
+	Availability             *float64      `json:"availability,omitempty"`
+	Category                 *string       `json:"category,omitempty"`
+	Comment                  *string       `json:"comment,omitempty"` // qBittorrent 5.0+
+	Completed                *int          `json:"completed,omitempty"`
+	CompletionOn             *int          `json:"completion_on,omitempty"`
+	ContentPath              *string       `json:"content_path,omitempty"`
+	DlLimit                  *int          `json:"dl_limit,omitempty"`
+	DlSpeed                  *int          `json:"dlspeed,omitempty"`
+	DownloadPath             *string       `json:"download_path,omitempty"`
+	Downloaded               *int          `json:"downloaded,omitempty"`
+	DownloadedSession        *int          `json:"downloaded_session,omitempty"`
+	Eta                      *int          `json:"eta,omitempty"`
+	FLPiecePrio              *bool         `json:"f_l_piece_prio,omitempty"`
+	ForceStart               *bool         `json:"force_start,omitempty"`
+	HasMetadata              *bool         `json:"has_metadata,omitempty"` // qBittorrent 5.0+
+	InactiveSeedingTimeLimit *int          `json:"inactive_seeding_time_limit,omitempty"`
+	InfohashV1               *string       `json:"infohash_v1,omitempty"`
+	InfohashV2               *string       `json:"infohash_v2,omitempty"`
+	LastActivity             *int          `json:"last_activity,omitempty"`
+	MagnetURI                *string       `json:"magnet_uri,omitempty"`
+	MaxInactiveSeedingTime   *int          `json:"max_inactive_seeding_time,omitempty"`
+	MaxRatio                 *float64      `json:"max_ratio,omitempty"`
+	MaxSeedingTime           *int          `json:"max_seeding_time,omitempty"`
+	Name                     *string       `json:"name,omitempty"`
+	NumComplete              *int          `json:"num_complete,omitempty"`
+	NumIncomplete            *int          `json:"num_incomplete,omitempty"`
+	NumLeechs                *int          `json:"num_leechs,omitempty"`
+	NumSeeds                 *int          `json:"num_seeds,omitempty"`
+	Popularity               *float64      `json:"popularity,omitempty"` // qBittorrent 5.0+
+	Priority                 *int          `json:"priority,omitempty"`
+	Private                  *bool         `json:"private,omitempty"` // qBittorrent 5.0+
+	Progress                 *float64      `json:"progress,omitempty"`
+	Ratio                    *float64      `json:"ratio,omitempty"`
+	RatioLimit               *float64      `json:"ratio_limit,omitempty"`
+	Reannounce               *int          `json:"reannounce,omitempty"` // qBittorrent 5.0+
+	SavePath                 *string       `json:"save_path,omitempty"`
+	SeedingTime              *int          `json:"seeding_time,omitempty"`
+	SeedingTimeLimit         *int          `json:"seeding_time_limit,omitempty"`
+	SeenComplete             *int          `json:"seen_complete,omitempty"`
+	SeqDl                    *bool         `json:"seq_dl,omitempty"`
+	Size                     *int          `json:"size,omitempty"`
+	State                    *TorrentState `json:"state,omitempty"`
+	SuperSeeding             *bool         `json:"super_seeding,omitempty"`
+	Tags                     *string       `json:"tags,omitempty"`
+	TimeActive               *int          `json:"time_active,omitempty"`
+	TotalSize                *int          `json:"total_size,omitempty"`
+	Tracker                  *string       `json:"tracker,omitempty"`
+	TrackersCount            *int          `json:"trackers_count,omitempty"`
+	UpLimit                  *int          `json:"up_limit,omitempty"`
+	Uploaded                 *int          `json:"uploaded,omitempty"`
+	UploadedSession          *int          `json:"uploaded_session,omitempty"`
+	UpSpeed                  *int          `json:"upspeed,omitempty"`
 }
 
 type SyncTorrentPeers struct {
-	Rid        int                        `json:"rid,omitempty"`
-	FullUpdate bool                       `json:"full_update,omitempty"`
-	ShowFlags  bool                       `json:"show_flags,omitempty"`
-	Peers      map[string]SyncTorrentPeer `json:"peers,omitempty"`
+	Rid          int                        `json:"rid,omitempty"`
+	FullUpdate   bool                       `json:"full_update,omitempty"`
+	ShowFlags    bool                       `json:"show_flags,omitempty"`
+	Peers        map[string]SyncTorrentPeer `json:"peers,omitempty"`         // property: "ip:port", value: changed fields
+	PeersRemoved []string                   `json:"peers_removed,omitempty"` // keys of the peers that disconnected
+
+	// raw response body, used by PeerTracker to merge partial updates
+	raw []byte
 }
 
 type SyncTorrentPeer struct {
@@ -76,8 +164,12 @@ type SyncTorrentPeer struct {
 }
 
 func (c *client) MainData(rid int) (*SyncMainData, error) {
-	apiUrl := fmt.Sprintf("%s/api/v2/sync/maindata?rid=%d", c.config.Address, rid)
-	result, err := c.doRequest(&requestData{
+	return c.MainDataContext(context.Background(), rid)
+}
+
+func (c *client) MainDataContext(ctx context.Context, rid int) (*SyncMainData, error) {
+	apiUrl := c.apiURL("sync/maindata", url.Values{"rid": {strconv.Itoa(rid)}})
+	result, err := c.doRequest(ctx, &requestData{
 		url: apiUrl,
 	})
 	if err != nil {
@@ -85,23 +177,28 @@ func (c *client) MainData(rid int) (*SyncMainData, error) {
 	}
 
 	if result.code != 200 {
-		return nil, errors.New("get main data failed: " + string(result.body))
+		return nil, newAPIError("get main data failed", result)
 	}
 
 	var mainData = new(SyncMainData)
 	if err := sonic.Unmarshal(result.body, mainData); err != nil {
 		return nil, err
 	}
+	mainData.raw = result.body
 
 	return mainData, nil
 }
 
 func (c *client) TorrentPeersData(hash string, rid int) (*SyncTorrentPeers, error) {
+	return c.TorrentPeersDataContext(context.Background(), hash, rid)
+}
+
+func (c *client) TorrentPeersDataContext(ctx context.Context, hash string, rid int) (*SyncTorrentPeers, error) {
 	var formData = url.Values{}
 	formData.Add("hash", hash)
 	formData.Add("rid", strconv.Itoa(rid))
-	apiUrl := fmt.Sprintf("%s/api/v2/sync/torrentPeers?%s", c.config.Address, formData.Encode())
-	result, err := c.doRequest(&requestData{
+	apiUrl := c.apiURL("sync/torrentPeers", formData)
+	result, err := c.doRequest(ctx, &requestData{
 		url: apiUrl,
 	})
 	if err != nil {
@@ -109,13 +206,14 @@ func (c *client) TorrentPeersData(hash string, rid int) (*SyncTorrentPeers, erro
 	}
 
 	if result.code != 200 {
-		return nil, errors.New("get torrent peers data failed: " + string(result.body))
+		return nil, newAPIError("get torrent peers data failed", result)
 	}
 
 	var mainData = new(SyncTorrentPeers)
 	if err := sonic.Unmarshal(result.body, mainData); err != nil {
 		return nil, err
 	}
+	mainData.raw = result.body
 
 	return mainData, nil
 }
@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/bytedance/sonic"
+	"github.com/xuthus5/qbittorrent-client-go/qbittorrent/qbittest"
 )
 
 func TestClient_MainData(t *testing.T) {
@@ -33,3 +34,34 @@ func TestClient_TorrentPeersData(t *testing.T) {
 	}
 	t.Log(string(bytes))
 }
+
+func TestClient_MainDataPartialUpdate(t *testing.T) {
+	client, server := newTestClient(t)
+	server.AddTorrent(&qbittest.Torrent{Hash: "8c212779b4abde7c6bc608063a0d008b7e40ce32", Name: "a", Size: 1024})
+
+	full, err := client.Sync().MainData(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !full.FullUpdate || len(full.Torrents) != 1 || full.ServerState.ConnectionStatus == nil {
+		t.Fatalf("expected a full update with one torrent, got %+v", full)
+	}
+
+	server.UpdateTorrent("8c212779b4abde7c6bc608063a0d008b7e40ce32", func(torrent *qbittest.Torrent) {
+		torrent.Progress = 0.5
+	})
+	delta, err := client.Sync().MainData(full.Rid)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if delta.FullUpdate || delta.Rid <= full.Rid {
+		t.Fatalf("expected a partial update after rid %d, got %+v", full.Rid, delta)
+	}
+	torrent := delta.Torrents["8c212779b4abde7c6bc608063a0d008b7e40ce32"]
+	if torrent.Progress == nil || *torrent.Progress != 0.5 {
+		t.Fatalf("expected the updated torrent in the delta, got %+v", delta.Torrents)
+	}
+	if torrent.Name != nil || delta.ServerState.ConnectionStatus != nil {
+		t.Fatalf("expected unchanged fields to be absent, got %+v", torrent)
+	}
+}
@@ -0,0 +1,233 @@
+package qbittorrent
+
+import (
+	"context"
+	"slices"
+	"sort"
+	"sync"
+
+	"github.com/bytedance/sonic"
+)
+
+// SyncState is a complete snapshot of the server built from /api/v2/sync/maindata
+type SyncState struct {
+	Rid         int                         // response id of the last applied update
+	Torrents    map[string]*TorrentInfo     // torrents by hash
+	Categories  map[string]*TorrentCategory // categories by name
+	Tags        []string                    // tags in alphabetical order
+	Trackers    map[string][]string         // hashes of the torrents using each tracker url
+	ServerState *ServerState                // global transfer info
+}
+
+// Syncer keeps a local copy of the server state up to date by applying the partial updates of
+// /api/v2/sync/maindata field by field. A Syncer is safe for concurrent use.
+type Syncer struct {
+	source Sync
+	// pollMu serializes Sync so that two polls never use the same rid
+	pollMu sync.Mutex
+
+	mu          sync.RWMutex
+	rid         int
+	torrents    map[string]map[string]any
+	categories  map[string]map[string]any
+	tags        map[string]struct{}
+	trackers    map[string][]string
+	serverState map[string]any
+
+	// decoded copies of the raw state, rebuilt for the changed entries only
+	torrentInfos   map[string]*TorrentInfo
+	categoryInfos  map[string]*TorrentCategory
+	serverStateVal *ServerState
+}
+
+// NewSyncer create a Syncer polling s, the first call of Sync fetches the full state
+func NewSyncer(s Sync) *Syncer {
+	syncer := &Syncer{source: s}
+	syncer.reset()
+	return syncer
+}
+
+// Sync fetch the changes since the last call and apply them
+func (s *Syncer) Sync(ctx context.Context) error {
+	s.pollMu.Lock()
+	defer s.pollMu.Unlock()
+	data, err := s.source.MainDataContext(ctx, s.Rid())
+	if err != nil {
+		return err
+	}
+	return s.Apply(data)
+}
+
+// Apply merge a response of /api/v2/sync/maindata into the state, it is called by Sync and only
+// needed when polling MainData manually
+func (s *Syncer) Apply(data *SyncMainData) error {
+	var raw = data.raw
+	if raw == nil {
+		var err error
+		if raw, err = sonic.Marshal(data); err != nil {
+			return err
+		}
+	}
+	var delta mainDataDelta
+	if err := sonic.Unmarshal(raw, &delta); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if delta.FullUpdate {
+		s.reset()
+	}
+	s.rid = delta.Rid
+
+	for hash, fields := range delta.Torrents {
+		s.torrents[hash] = mergeFields(s.torrents[hash], fields)
+		var info = new(TorrentInfo)
+		if err := decodeFields(s.torrents[hash], info); err != nil {
+			return err
+		}
+		info.Hash = hash
+		s.torrentInfos[hash] = info
+	}
+	for _, hash := range delta.TorrentsRemoved {
+		delete(s.torrents, hash)
+		delete(s.torrentInfos, hash)
+	}
+
+	for name, fields := range delta.Categories {
+		s.categories[name] = mergeFields(s.categories[name], fields)
+		var category = new(TorrentCategory)
+		if err := decodeFields(s.categories[name], category); err != nil {
+			return err
+		}
+		s.categoryInfos[name] = category
+	}
+	for _, name := range delta.CategoriesRemoved {
+		delete(s.categories, name)
+		delete(s.categoryInfos, name)
+	}
+
+	for _, tag := range delta.Tags {
+		s.tags[tag] = struct{}{}
+	}
+	for _, tag := range delta.TagsRemoved {
+		delete(s.tags, tag)
+	}
+
+	for tracker, hashes := range delta.Trackers {
+		s.trackers[tracker] = hashes
+	}
+	for _, tracker := range delta.TrackersRemoved {
+		delete(s.trackers, tracker)
+	}
+
+	if len(delta.ServerState) != 0 {
+		s.serverState = mergeFields(s.serverState, delta.ServerState)
+		var state = new(ServerState)
+		if err := decodeFields(s.serverState, state); err != nil {
+			return err
+		}
+		s.serverStateVal = state
+	}
+	return nil
+}
+
+// Rid returns the response id of the last applied update, 0 before the first update
+func (s *Syncer) Rid() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.rid
+}
+
+// Torrent returns a copy of a torrent of the state
+func (s *Syncer) Torrent(hash string) (*TorrentInfo, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	info, ok := s.torrentInfos[hash]
+	if !ok {
+		return nil, false
+	}
+	torrent := *info
+	return &torrent, true
+}
+
+// Snapshot returns a copy of the current state, the copy is not modified by later updates
+func (s *Syncer) Snapshot() *SyncState {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	var state = &SyncState{
+		Rid:        s.rid,
+		Torrents:   make(map[string]*TorrentInfo, len(s.torrentInfos)),
+		Categories: make(map[string]*TorrentCategory, len(s.categoryInfos)),
+		Tags:       make([]string, 0, len(s.tags)),
+		Trackers:   make(map[string][]string, len(s.trackers)),
+	}
+	for hash, info := range s.torrentInfos {
+		torrent := *info
+		state.Torrents[hash] = &torrent
+	}
+	for name, info := range s.categoryInfos {
+		category := *info
+		state.Categories[name] = &category
+	}
+	for tag := range s.tags {
+		state.Tags = append(state.Tags, tag)
+	}
+	sort.Strings(state.Tags)
+	for tracker, hashes := range s.trackers {
+		state.Trackers[tracker] = slices.Clone(hashes)
+	}
+	if s.serverStateVal != nil {
+		serverState := *s.serverStateVal
+		state.ServerState = &serverState
+	}
+	return state
+}
+
+// reset drop the state, the caller must hold mu
+func (s *Syncer) reset() {
+	s.rid = 0
+	s.torrents = make(map[string]map[string]any)
+	s.categories = make(map[string]map[string]any)
+	s.tags = make(map[string]struct{})
+	s.trackers = make(map[string][]string)
+	s.serverState = make(map[string]any)
+	s.torrentInfos = make(map[string]*TorrentInfo)
+	s.categoryInfos = make(map[string]*TorrentCategory)
+	s.serverStateVal = nil
+}
+
+// mainDataDelta is the raw form of a /api/v2/sync/maindata response
+type mainDataDelta struct {
+	Rid               int                       `json:"rid"`
+	FullUpdate        bool                      `json:"full_update"`
+	Torrents          map[string]map[string]any `json:"torrents"`
+	TorrentsRemoved   []string                  `json:"torrents_removed"`
+	Categories        map[string]map[string]any `json:"categories"`
+	CategoriesRemoved []string                  `json:"categories_removed"`
+	Tags              []string                  `json:"tags"`
+	TagsRemoved       []string                  `json:"tags_removed"`
+	Trackers          map[string][]string       `json:"trackers"`
+	TrackersRemoved   []string                  `json:"trackers_removed"`
+	ServerState       map[string]any            `json:"server_state"`
+}
+
+// mergeFields copy the fields of a partial update into dst, dst is created if nil
+func mergeFields(dst, fields map[string]any) map[string]any {
+	if dst == nil {
+		dst = make(map[string]any, len(fields))
+	}
+	for key, value := range fields {
+		dst[key] = value
+	}
+	return dst
+}
+
+// decodeFields decode merged raw fields into v
+func decodeFields(fields map[string]any, v any) error {
+	data, err := sonic.Marshal(fields)
+	if err != nil {
+		return err
+	}
+	return sonic.Unmarshal(data, v)
+}
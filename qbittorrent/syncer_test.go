@@ -0,0 +1,89 @@
+package qbittorrent
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/xuthus5/qbittorrent-client-go/qbittorrent/qbittest"
+)
+
+func TestSyncer(t *testing.T) {
+	client, server := newTestClient(t)
+	server.AddTorrent(&qbittest.Torrent{
+		Hash:     "8c212779b4abde7c6bc608063a0d008b7e40ce32",
+		Name:     "ubuntu-24.04-desktop-amd64.iso",
+		Category: "linux",
+		Tags:     []string{"iso"},
+		Size:     6 << 30,
+		Trackers: []*qbittest.Tracker{{URL: "https://torrent.ubuntu.com/announce", Status: 2}},
+	})
+	server.AddTorrent(&qbittest.Torrent{Hash: "d1f0ec5b4ab3a0c5aa8e2b0f3c1d6b4cf0a6a3d2", Name: "debian-12.5.0-amd64-netinst.iso"})
+
+	syncer := NewSyncer(client.Sync())
+	if err := syncer.Sync(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	state := syncer.Snapshot()
+	if len(state.Torrents) != 2 || state.Categories["linux"] == nil || !slices.Equal(state.Tags, []string{"iso"}) {
+		t.Fatalf("unexpected initial state: %+v", state)
+	}
+	if torrent := state.Torrents["8c212779b4abde7c6bc608063a0d008b7e40ce32"]; torrent.Hash == "" || torrent.Name != "ubuntu-24.04-desktop-amd64.iso" {
+		t.Fatalf("unexpected torrent: %+v", torrent)
+	}
+
+	server.UpdateTorrent("8c212779b4abde7c6bc608063a0d008b7e40ce32", func(torrent *qbittest.Torrent) {
+		torrent.Progress = 0.5
+	})
+	server.RemoveTorrent("d1f0ec5b4ab3a0c5aa8e2b0f3c1d6b4cf0a6a3d2")
+	if err := client.Torrent().CreateTags([]string{"seeding"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := syncer.Sync(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	torrent, ok := syncer.Torrent("8c212779b4abde7c6bc608063a0d008b7e40ce32")
+	if !ok || torrent.Progress != 0.5 || torrent.Name != "ubuntu-24.04-desktop-amd64.iso" || torrent.Category != "linux" {
+		t.Fatalf("partial update was not merged: %+v", torrent)
+	}
+	if _, ok := syncer.Torrent("d1f0ec5b4ab3a0c5aa8e2b0f3c1d6b4cf0a6a3d2"); ok {
+		t.Fatal("removed torrent is still in the state")
+	}
+	if tags := syncer.Snapshot().Tags; !slices.Equal(tags, []string{"iso", "seeding"}) {
+		t.Fatalf("unexpected tags: %v", tags)
+	}
+	if state.Torrents["8c212779b4abde7c6bc608063a0d008b7e40ce32"].Progress != 0 {
+		t.Fatal("snapshot was modified by a later update")
+	}
+}
+
+func TestSyncer_Apply(t *testing.T) {
+	syncer := NewSyncer(nil)
+	updates := []string{
+		`{"rid":1,"full_update":true,"torrents":{"a":{"name":"a","state":"downloading","progress":0.1}},"categories":{"tv":{"name":"tv","savePath":"/tv"}},"tags":["x"],"trackers":{"udp://t":["a"]},"server_state":{"dl_info_speed":100,"up_info_speed":5}}`,
+		`{"rid":2,"torrents":{"a":{"progress":0.2},"b":{"name":"b"}},"categories_removed":["tv"],"tags_removed":["x"],"trackers_removed":["udp://t"],"server_state":{"dl_info_speed":200}}`,
+	}
+	for _, update := range updates {
+		if err := syncer.Apply(&SyncMainData{raw: []byte(update)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	state := syncer.Snapshot()
+	if a := state.Torrents["a"]; a.Name != "a" || a.State != "downloading" || a.Progress != 0.2 {
+		t.Fatalf("unexpected torrent: %+v", a)
+	}
+	if state.Rid != 2 || len(state.Torrents) != 2 || len(state.Categories) != 0 || len(state.Tags) != 0 || len(state.Trackers) != 0 {
+		t.Fatalf("unexpected state: %+v", state)
+	}
+	if *state.ServerState.DlInfoSpeed != 200 || *state.ServerState.UpInfoSpeed != 5 {
+		t.Fatalf("unexpected server state: %+v", state.ServerState)
+	}
+
+	if err := syncer.Apply(&SyncMainData{raw: []byte(`{"rid":3,"full_update":true,"torrents":{"c":{"name":"c"}}}`)}); err != nil {
+		t.Fatal(err)
+	}
+	if state := syncer.Snapshot(); len(state.Torrents) != 1 || state.Torrents["c"] == nil || state.ServerState != nil {
+		t.Fatalf("full update did not reset the state: %+v", state)
+	}
+}
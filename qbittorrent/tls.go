@@ -0,0 +1,70 @@
+package qbittorrent
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+)
+
+// TLSOption TLS configuration of the connections to the WebUI, certificate verification is
+// enabled unless InsecureSkipVerify is set
+type TLSOption struct {
+	// CAFile path of a PEM encoded CA bundle used to verify the server, the system roots are used if empty
+	CAFile string
+	// CAPEM PEM encoded CA bundle, appended to the CAFile certificates
+	CAPEM []byte
+	// CertFile path of the PEM encoded client certificate, used for mTLS together with KeyFile
+	CertFile string
+	// KeyFile path of the PEM encoded private key of the client certificate
+	KeyFile string
+	// ServerName overrides the server name used for SNI and certificate verification
+	ServerName string
+	// MinVersion minimum TLS version such as tls.VersionTLS12, default TLS 1.2
+	MinVersion uint16
+	// InsecureSkipVerify disable the certificate verification, only for testing or self-signed certificates
+	InsecureSkipVerify bool
+}
+
+// newTLSConfig build the tls.Config of the option, a nil option returns the default configuration
+func newTLSConfig(opt *TLSOption) (*tls.Config, error) {
+	var cfg = &tls.Config{MinVersion: tls.VersionTLS12}
+	if opt == nil {
+		return cfg, nil
+	}
+	if opt.MinVersion != 0 {
+		cfg.MinVersion = opt.MinVersion
+	}
+	cfg.ServerName = opt.ServerName
+	cfg.InsecureSkipVerify = opt.InsecureSkipVerify
+
+	if opt.CAFile != "" || len(opt.CAPEM) > 0 {
+		var pool = x509.NewCertPool()
+		if opt.CAFile != "" {
+			pem, err := os.ReadFile(opt.CAFile)
+			if err != nil {
+				return nil, fmt.Errorf("read ca file: %w", err)
+			}
+			if !pool.AppendCertsFromPEM(pem) {
+				return nil, fmt.Errorf("no certificate found in ca file %s", opt.CAFile)
+			}
+		}
+		if len(opt.CAPEM) > 0 && !pool.AppendCertsFromPEM(opt.CAPEM) {
+			return nil, errors.New("no certificate found in ca pem")
+		}
+		cfg.RootCAs = pool
+	}
+
+	if opt.CertFile != "" || opt.KeyFile != "" {
+		if opt.CertFile == "" || opt.KeyFile == "" {
+			return nil, errors.New("both cert file and key file are required for the client certificate")
+		}
+		cert, err := tls.LoadX509KeyPair(opt.CertFile, opt.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("load client certificate: %w", err)
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+	return cfg, nil
+}
@@ -0,0 +1,127 @@
+package qbittorrent
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/xuthus5/qbittorrent-client-go/qbittorrent/qbittest"
+)
+
+func TestClient_TLS(t *testing.T) {
+	server := qbittest.NewTLSServer()
+	defer server.Close()
+	caPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+
+	newConfig := func(opt *TLSOption) *Config {
+		return &Config{
+			Address:  server.URL,
+			Username: qbittest.DefaultUsername,
+			Password: qbittest.DefaultPassword,
+			TLS:      opt,
+		}
+	}
+
+	_, err := NewClient(newConfig(nil))
+	var unknownAuthority x509.UnknownAuthorityError
+	if !errors.As(err, &unknownAuthority) {
+		t.Fatalf("expected the certificate verification to fail, got %v", err)
+	}
+
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	if err := os.WriteFile(caFile, caPEM, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	for name, opt := range map[string]*TLSOption{
+		"ca pem":   {CAPEM: caPEM},
+		"ca file":  {CAFile: caFile},
+		"insecure": {InsecureSkipVerify: true},
+	} {
+		client, err := NewClient(newConfig(opt))
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if _, err := client.Application().Version(); err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+	}
+
+	_, err = NewClient(newConfig(&TLSOption{CAPEM: caPEM, ServerName: "qbittorrent.example.org"}))
+	var hostnameErr x509.HostnameError
+	if !errors.As(err, &hostnameErr) {
+		t.Fatalf("expected the server name to be verified, got %v", err)
+	}
+}
+
+func TestNewTLSConfig(t *testing.T) {
+	cfg, err := newTLSConfig(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.InsecureSkipVerify || cfg.MinVersion != tls.VersionTLS12 {
+		t.Fatalf("unexpected default config: %+v", cfg)
+	}
+
+	certFile, keyFile := writeClientCertificate(t)
+	cfg, err = newTLSConfig(&TLSOption{CertFile: certFile, KeyFile: keyFile, MinVersion: tls.VersionTLS13})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cfg.Certificates) != 1 || cfg.MinVersion != tls.VersionTLS13 {
+		t.Fatalf("unexpected config: %+v", cfg)
+	}
+
+	if _, err := newTLSConfig(&TLSOption{CertFile: certFile}); err == nil {
+		t.Fatal("expected an error without key file")
+	}
+	if _, err := newTLSConfig(&TLSOption{CAPEM: []byte("not a certificate")}); err == nil {
+		t.Fatal("expected an error for an invalid ca")
+	}
+	if _, err := newTLSConfig(&TLSOption{CAFile: filepath.Join(t.TempDir(), "missing.pem")}); err == nil {
+		t.Fatal("expected an error for a missing ca file")
+	}
+}
+
+// writeClientCertificate writes a self-signed certificate and its key, returns the file paths
+func writeClientCertificate(t *testing.T) (string, string) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "qbittorrent-client"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir := t.TempDir()
+	certFile, keyFile := filepath.Join(dir, "client.pem"), filepath.Join(dir, "client-key.pem")
+	if err := os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return certFile, keyFile
+}
@@ -1,11 +1,9 @@
 package qbittorrent
 
 import (
-	"bytes"
+	"context"
 	"errors"
-	"fmt"
 	"io"
-	"mime/multipart"
 	"net/http"
 	"net/url"
 	"strconv"
@@ -17,108 +15,214 @@ import (
 type Torrent interface {
 	// GetTorrents get torrent list
 	GetTorrents(opt *TorrentOption) ([]*TorrentInfo, error)
+	// GetTorrentsContext is the context-aware version of GetTorrents
+	GetTorrentsContext(ctx context.Context, opt *TorrentOption) ([]*TorrentInfo, error)
 	// GetProperties get torrent generic properties
 	GetProperties(hash string) (*TorrentProperties, error)
+	// GetPropertiesContext is the context-aware version of GetProperties
+	GetPropertiesContext(ctx context.Context, hash string) (*TorrentProperties, error)
 	// GetTrackers get torrent trackers
 	GetTrackers(hash string) ([]*TorrentTracker, error)
+	// GetTrackersContext is the context-aware version of GetTrackers
+	GetTrackersContext(ctx context.Context, hash string) ([]*TorrentTracker, error)
 	// GetWebSeeds get torrent web seeds
 	GetWebSeeds(hash string) ([]*TorrentWebSeed, error)
+	// GetWebSeedsContext is the context-aware version of GetWebSeeds
+	GetWebSeedsContext(ctx context.Context, hash string) ([]*TorrentWebSeed, error)
 	// GetContents get torrent contents, indexes(optional) of the files you want to retrieve
 	GetContents(hash string, indexes ...string) ([]*TorrentContent, error)
+	// GetContentsContext is the context-aware version of GetContents
+	GetContentsContext(ctx context.Context, hash string, indexes ...string) ([]*TorrentContent, error)
 	// GetPiecesStates get torrent pieces states
 	GetPiecesStates(hash string) ([]int, error)
+	// GetPiecesStatesContext is the context-aware version of GetPiecesStates
+	GetPiecesStatesContext(ctx context.Context, hash string) ([]int, error)
 	// GetPiecesHashes get torrent pieces hashes
 	GetPiecesHashes(hash string) ([]string, error)
-	// PauseTorrents the hashes of the torrents you want to pause
+	// GetPiecesHashesContext is the context-aware version of GetPiecesHashes
+	GetPiecesHashesContext(ctx context.Context, hash string) ([]string, error)
+	// PauseTorrents the hashes of the torrents you want to pause, torrents/stop is used on qBittorrent 5.0+
 	PauseTorrents(hashes []string) error
-	// ResumeTorrents the hashes of the torrents you want to resume
+	// PauseTorrentsContext is the context-aware version of PauseTorrents
+	PauseTorrentsContext(ctx context.Context, hashes []string) error
+	// ResumeTorrents the hashes of the torrents you want to resume, torrents/start is used on qBittorrent 5.0+
 	ResumeTorrents(hashes []string) error
+	// ResumeTorrentsContext is the context-aware version of ResumeTorrents
+	ResumeTorrentsContext(ctx context.Context, hashes []string) error
 	// DeleteTorrents the hashes of the torrents you want to delete, if set deleteFile to true,
 	// the downloaded data will also be deleted, otherwise has no effect.
 	DeleteTorrents(hashes []string, deleteFile bool) error
+	// DeleteTorrentsContext is the context-aware version of DeleteTorrents
+	DeleteTorrentsContext(ctx context.Context, hashes []string, deleteFile bool) error
 	// RecheckTorrents the hashes of the torrents you want to recheck
 	RecheckTorrents(hashes []string) error
+	// RecheckTorrentsContext is the context-aware version of RecheckTorrents
+	RecheckTorrentsContext(ctx context.Context, hashes []string) error
 	// ReAnnounceTorrents the hashes of the torrents you want to reannounce
 	ReAnnounceTorrents(hashes []string) error
-	// AddNewTorrent add torrents from server local file or from URLs. http://, https://,
-	// magnet: and bc://bt/ links are supported, but only one onetime
-	AddNewTorrent(opt *TorrentAddOption) error
+	// ReAnnounceTorrentsContext is the context-aware version of ReAnnounceTorrents
+	ReAnnounceTorrentsContext(ctx context.Context, hashes []string) error
+	// AddNewTorrent add torrents from torrent files and URLs by one request. http://, https://,
+	// magnet: and bc://bt/ links are supported. the torrent files are parsed before upload and
+	// streamed, the hashes of the submitted torrent files and magnet links are returned, the
+	// hashes of torrents added by http urls are unknown
+	AddNewTorrent(opt *TorrentAddOption) ([]string, error)
+	// AddNewTorrentContext is the context-aware version of AddNewTorrent
+	AddNewTorrentContext(ctx context.Context, opt *TorrentAddOption) ([]string, error)
+	// AddTorrents add many torrent files and URLs, such as the files of a watch directory, and
+	// report the result of each item in the order of URLs then Torrents. unreadable and invalid
+	// files are skipped, the others are uploaded in batches. an error joining the errors of the
+	// items is returned with the results when no item was submitted
+	AddTorrents(opt *TorrentAddOption) ([]*TorrentAddResult, error)
+	// AddTorrentsContext is the context-aware version of AddTorrents
+	AddTorrentsContext(ctx context.Context, opt *TorrentAddOption) ([]*TorrentAddResult, error)
 	// AddTrackers add trackers to torrent
 	AddTrackers(hash string, urls []string) error
+	// AddTrackersContext is the context-aware version of AddTrackers
+	AddTrackersContext(ctx context.Context, hash string, urls []string) error
 	// EditTrackers edit trackers
 	EditTrackers(hash, origUrl, newUrl string) error
+	// EditTrackersContext is the context-aware version of EditTrackers
+	EditTrackersContext(ctx context.Context, hash, origUrl, newUrl string) error
 	// RemoveTrackers remove trackers
 	RemoveTrackers(hash string, urls []string) error
+	// RemoveTrackersContext is the context-aware version of RemoveTrackers
+	RemoveTrackersContext(ctx context.Context, hash string, urls []string) error
 	// AddPeers add peers for torrent, each peer is host:port
 	AddPeers(hashes []string, peers []string) error
+	// AddPeersContext is the context-aware version of AddPeers
+	AddPeersContext(ctx context.Context, hashes []string, peers []string) error
 	// IncreasePriority increase torrent priority
 	IncreasePriority(hashes []string) error
+	// IncreasePriorityContext is the context-aware version of IncreasePriority
+	IncreasePriorityContext(ctx context.Context, hashes []string) error
 	// DecreasePriority decrease torrent priority
 	DecreasePriority(hashes []string) error
+	// DecreasePriorityContext is the context-aware version of DecreasePriority
+	DecreasePriorityContext(ctx context.Context, hashes []string) error
 	// MaxPriority maximal torrent priority
 	MaxPriority(hashes []string) error
+	// MaxPriorityContext is the context-aware version of MaxPriority
+	MaxPriorityContext(ctx context.Context, hashes []string) error
 	// MinPriority minimal torrent priority
 	MinPriority(hashes []string) error
+	// MinPriorityContext is the context-aware version of MinPriority
+	MinPriorityContext(ctx context.Context, hashes []string) error
 	// SetFilePriority set file priority
 	SetFilePriority(hash string, id string, priority int) error
+	// SetFilePriorityContext is the context-aware version of SetFilePriority
+	SetFilePriorityContext(ctx context.Context, hash string, id string, priority int) error
 	// GetDownloadLimit get torrent download limit
 	GetDownloadLimit(hashes []string) (map[string]int, error)
+	// GetDownloadLimitContext is the context-aware version of GetDownloadLimit
+	GetDownloadLimitContext(ctx context.Context, hashes []string) (map[string]int, error)
 	// SetDownloadLimit set torrent download limit, limit in bytes per second, if no limit please set value zero
 	SetDownloadLimit(hashes []string, limit int) error
+	// SetDownloadLimitContext is the context-aware version of SetDownloadLimit
+	SetDownloadLimitContext(ctx context.Context, hashes []string, limit int) error
 	// SetShareLimit set torrent share limit, ratioLimit: the maximum seeding ratio for the torrent, -2 means the
 	// global limit should be used, -1 means no limit; seedingTimeLimit: the maximum seeding time (minutes) for the
 	// torrent, -2 means the global limit should be used, -1 means no limit; inactiveSeedingTimeLimit: the maximum
 	// amount of time (minutes) the torrent is allowed to seed while being inactive, -2 means the global limit should
 	// be used, -1 means no limit.
 	SetShareLimit(hashes []string, ratioLimit float64, seedingTimeLimit, inactiveSeedingTimeLimit int) error
+	// SetShareLimitContext is the context-aware version of SetShareLimit
+	SetShareLimitContext(ctx context.Context, hashes []string, ratioLimit float64, seedingTimeLimit, inactiveSeedingTimeLimit int) error
 	// GetUploadLimit get torrent upload limit
 	GetUploadLimit(hashes []string) (map[string]int, error)
+	// GetUploadLimitContext is the context-aware version of GetUploadLimit
+	GetUploadLimitContext(ctx context.Context, hashes []string) (map[string]int, error)
 	// SetUploadLimit set torrent upload limit
 	SetUploadLimit(hashes []string, limit int) error
+	// SetUploadLimitContext is the context-aware version of SetUploadLimit
+	SetUploadLimitContext(ctx context.Context, hashes []string, limit int) error
 	// SetLocation set torrent location
 	SetLocation(hashes []string, location string) error
+	// SetLocationContext is the context-aware version of SetLocation
+	SetLocationContext(ctx context.Context, hashes []string, location string) error
 	// SetName set torrent name
 	SetName(hash string, name string) error
+	// SetNameContext is the context-aware version of SetName
+	SetNameContext(ctx context.Context, hash string, name string) error
 	// SetCategory set torrent category
 	SetCategory(hashes []string, category string) error
+	// SetCategoryContext is the context-aware version of SetCategory
+	SetCategoryContext(ctx context.Context, hashes []string, category string) error
 	// GetCategories get all categories
 	GetCategories() (map[string]*TorrentCategory, error)
+	// GetCategoriesContext is the context-aware version of GetCategories
+	GetCategoriesContext(ctx context.Context) (map[string]*TorrentCategory, error)
 	// AddNewCategory add new category
 	AddNewCategory(category, savePath string) error
+	// AddNewCategoryContext is the context-aware version of AddNewCategory
+	AddNewCategoryContext(ctx context.Context, category, savePath string) error
 	// EditCategory edit category
 	EditCategory(category, savePath string) error
+	// EditCategoryContext is the context-aware version of EditCategory
+	EditCategoryContext(ctx context.Context, category, savePath string) error
 	// RemoveCategories remove categories
 	RemoveCategories(categories []string) error
+	// RemoveCategoriesContext is the context-aware version of RemoveCategories
+	RemoveCategoriesContext(ctx context.Context, categories []string) error
 	// AddTags add torrent tags
 	AddTags(hashes []string, tags []string) error
+	// AddTagsContext is the context-aware version of AddTags
+	AddTagsContext(ctx context.Context, hashes []string, tags []string) error
+	// SetTags replace the tags of the torrents, requires qBittorrent 5.1+ (webapi 2.11.4), ErrUnsupported
+	// is returned by older servers
+	SetTags(hashes []string, tags []string) error
+	// SetTagsContext is the context-aware version of SetTags
+	SetTagsContext(ctx context.Context, hashes []string, tags []string) error
 	// RemoveTags remove torrent tags
 	RemoveTags(hashes []string, tags []string) error
+	// RemoveTagsContext is the context-aware version of RemoveTags
+	RemoveTagsContext(ctx context.Context, hashes []string, tags []string) error
 	// GetTags get all tags
 	GetTags() ([]string, error)
+	// GetTagsContext is the context-aware version of GetTags
+	GetTagsContext(ctx context.Context) ([]string, error)
 	// CreateTags create tags
 	CreateTags(tags []string) error
+	// CreateTagsContext is the context-aware version of CreateTags
+	CreateTagsContext(ctx context.Context, tags []string) error
 	// DeleteTags delete tags
 	DeleteTags(tags []string) error
+	// DeleteTagsContext is the context-aware version of DeleteTags
+	DeleteTagsContext(ctx context.Context, tags []string) error
 	// SetAutomaticManagement set automatic torrent management
 	SetAutomaticManagement(hashes []string, enable bool) error
+	// SetAutomaticManagementContext is the context-aware version of SetAutomaticManagement
+	SetAutomaticManagementContext(ctx context.Context, hashes []string, enable bool) error
 	// ToggleSequentialDownload toggle sequential download
 	ToggleSequentialDownload(hashes []string) error
+	// ToggleSequentialDownloadContext is the context-aware version of ToggleSequentialDownload
+	ToggleSequentialDownloadContext(ctx context.Context, hashes []string) error
 	// SetFirstLastPiecePriority set first/last piece priority
 	SetFirstLastPiecePriority(hashes []string) error
+	// SetFirstLastPiecePriorityContext is the context-aware version of SetFirstLastPiecePriority
+	SetFirstLastPiecePriorityContext(ctx context.Context, hashes []string) error
 	// SetForceStart set force start
 	SetForceStart(hashes []string, force bool) error
+	// SetForceStartContext is the context-aware version of SetForceStart
+	SetForceStartContext(ctx context.Context, hashes []string, force bool) error
 	// SetSuperSeeding set super seeding
 	SetSuperSeeding(hashes []string, enable bool) error
+	// SetSuperSeedingContext is the context-aware version of SetSuperSeeding
+	SetSuperSeedingContext(ctx context.Context, hashes []string, enable bool) error
 	// RenameFile rename file
 	RenameFile(hash, oldPath, newPath string) error
+	// RenameFileContext is the context-aware version of RenameFile
+	RenameFileContext(ctx context.Context, hash, oldPath, newPath string) error
 	// RenameFolder rename folder
 	RenameFolder(hash, oldPath, newPath string) error
+	// RenameFolderContext is the context-aware version of RenameFolder
+	RenameFolderContext(ctx context.Context, hash, oldPath, newPath string) error
 }
 
 type TorrentOption struct {
-	// Filter torrent list by state. Allowed state filters: all,downloading,seeding,completed,paused,
-	// active,inactive,resumed,stalled,stalled_uploading,stalled_downloading,errored
-	Filter string `schema:"filter,omitempty"`
+	// Filter torrent list by state, see the Filter constants. qBittorrent 5.0 renamed paused and resumed
+	// to stopped and running, either name is sent with the name of the server version
+	Filter TorrentFilter `schema:"filter,omitempty"`
 	// Category get torrents with the given category, empty string means "without category"; no "category"
 	// parameter means "any category"
 	Category string `schema:"category,omitempty"`
@@ -137,57 +241,58 @@ type TorrentOption struct {
 }
 
 type TorrentInfo struct {
-	AddedOn                  int     `json:"added_on"`
-	AmountLeft               int     `json:"amount_left"`
-	AutoTmm                  bool    `json:"auto_tmm"`
-	Availability             int     `json:"availability"`
-	Category                 string  `json:"category"`
-	Completed                int     `json:"completed"`
-	CompletionOn             int     `json:"completion_on"`
-	ContentPath              string  `json:"content_path"`
-	DlLimit                  int     `json:"dl_limit"`
-	Dlspeed                  int     `json:"dlspeed"`
-	DownloadPath             string  `json:"download_path"`
-	Downloaded               int     `json:"downloaded"`
-	DownloadedSession        int     `json:"downloaded_session"`
-	Eta                      int     `json:"eta"`
-	FLPiecePrio              bool    `json:"f_l_piece_prio"`
-	ForceStart               bool    `json:"force_start"`
-	Hash                     string  `json:"hash"`
-	InactiveSeedingTimeLimit int     `json:"inactive_seeding_time_limit"`
-	InfohashV1               string  `json:"infohash_v1"`
-	InfohashV2               string  `json:"infohash_v2"`
-	LastActivity             int     `json:"last_activity"`
-	MagnetURI                string  `json:"magnet_uri"`
-	MaxInactiveSeedingTime   int     `json:"max_inactive_seeding_time"`
-	MaxRatio                 int     `json:"max_ratio"`
-	MaxSeedingTime           int     `json:"max_seeding_time"`
-	Name                     string  `json:"name"`
-	NumComplete              int     `json:"num_complete"`
-	NumIncomplete            int     `json:"num_incomplete"`
-	NumLeechs                int     `json:"num_leechs"`
-	NumSeeds                 int     `json:"num_seeds"`
-	Priority                 int     `json:"priority"`
-	Progress                 int     `json:"progress"`
-	Ratio                    float64 `json:"ratio"`
-	RatioLimit               int     `json:"ratio_limit"`
-	SavePath                 string  `json:"save_path"`
-	SeedingTime              int     `json:"seeding_time"`
-	SeedingTimeLimit         int     `json:"seeding_time_limit"`
-	SeenComplete             int     `json:"seen_complete"`
-	SeqDl                    bool    `json:"seq_dl"`
-	Size                     int     `json:"size"`
-	State                    string  `json:"state"`
-	SuperSeeding             bool    `json:"super_seeding"`
-	Tags                     string  `json:"tags"`
-	TimeActive               int     `json:"time_active"`
-	TotalSize                int     `json:"total_size"`
-	Tracker                  string  `json:"tracker"`
-	TrackersCount            int     `json:"trackers_count"`
-	UpLimit                  int     `json:"up_limit"`
-	Uploaded                 int     `json:"uploaded"`
-	UploadedSession          int     `json:"uploaded_session"`
-	Upspeed                  int     `json:"upspeed"`
+	AddedOn                  int          `json:"added_on"`
+	AmountLeft               int          `json:"amount_left"`
+	AutoTmm                  bool         `json:"auto_tmm"`
+	Availability             float64      `json:"availability"`
+	Category                 string       `json:"category"`
+	Completed                int          `json:"completed"`
+	CompletionOn             int          `json:"completion_on"`
+	ContentPath              string       `json:"content_path"`
+	DlLimit                  int          `json:"dl_limit"`
+	Dlspeed                  int          `json:"dlspeed"`
+	DownloadPath             string       `json:"download_path"`
+	Downloaded               int          `json:"downloaded"`
+	DownloadedSession        int          `json:"downloaded_session"`
+	Eta                      int          `json:"eta"`
+	FLPiecePrio              bool         `json:"f_l_piece_prio"`
+	ForceStart               bool         `json:"force_start"`
+	Hash                     string       `json:"hash"`
+	HasMetadata              *bool        `json:"has_metadata,omitempty"` // qBittorrent 5.0+
+	InactiveSeedingTimeLimit int          `json:"inactive_seeding_time_limit"`
+	InfohashV1               string       `json:"infohash_v1"`
+	InfohashV2               string       `json:"infohash_v2"`
+	LastActivity             int          `json:"last_activity"`
+	MagnetURI                string       `json:"magnet_uri"`
+	MaxInactiveSeedingTime   int          `json:"max_inactive_seeding_time"`
+	MaxRatio                 float64      `json:"max_ratio"`
+	MaxSeedingTime           int          `json:"max_seeding_time"`
+	Name                     string       `json:"name"`
+	NumComplete              int          `json:"num_complete"`
+	NumIncomplete            int          `json:"num_incomplete"`
+	NumLeechs                int          `json:"num_leechs"`
+	NumSeeds                 int          `json:"num_seeds"`
+	Priority                 int          `json:"priority"`
+	Progress                 float64      `json:"progress"`
+	Ratio                    float64      `json:"ratio"`
+	RatioLimit               float64      `json:"ratio_limit"`
+	SavePath                 string       `json:"save_path"`
+	SeedingTime              int          `json:"seeding_time"`
+	SeedingTimeLimit         int          `json:"seeding_time_limit"`
+	SeenComplete             int          `json:"seen_complete"`
+	SeqDl                    bool         `json:"seq_dl"`
+	Size                     int          `json:"size"`
+	State                    TorrentState `json:"state"`
+	SuperSeeding             bool         `json:"super_seeding"`
+	Tags                     string       `json:"tags"`
+	TimeActive               int          `json:"time_active"`
+	TotalSize                int          `json:"total_size"`
+	Tracker                  string       `json:"tracker"`
+	TrackersCount            int          `json:"trackers_count"`
+	UpLimit                  int          `json:"up_limit"`
+	Uploaded                 int          `json:"uploaded"`
+	UploadedSession          int          `json:"uploaded_session"`
+	Upspeed                  int          `json:"upspeed"`
 }
 
 type TorrentProperties struct {
@@ -248,14 +353,14 @@ type TorrentWebSeed struct {
 }
 
 type TorrentContent struct {
-	Availability int    `json:"availability,omitempty"`
-	Index        int    `json:"index,omitempty"`
-	IsSeed       bool   `json:"is_seed,omitempty"`
-	Name         string `json:"name,omitempty"`
-	PieceRange   []int  `json:"piece_range,omitempty"`
-	Priority     int    `json:"priority,omitempty"`
-	Progress     int    `json:"progress,omitempty"`
-	Size         int64  `json:"size,omitempty"`
+	Availability float64 `json:"availability,omitempty"`
+	Index        int     `json:"index,omitempty"`
+	IsSeed       bool    `json:"is_seed,omitempty"`
+	Name         string  `json:"name,omitempty"`
+	PieceRange   []int   `json:"piece_range,omitempty"`
+	Priority     int     `json:"priority,omitempty"`
+	Progress     float64 `json:"progress,omitempty"`
+	Size         int64   `json:"size,omitempty"`
 }
 
 type TorrentAddFileMetadata struct {
@@ -264,26 +369,112 @@ type TorrentAddFileMetadata struct {
 	Filename string
 	// Data read torrent file content and set to here
 	Data []byte
+	// Open opens the torrent file when Data is nil, it is called every time the file is read,
+	// see TorrentFileFromPath and TorrentFilesFromFS
+	Open func() (io.ReadCloser, error)
+	// source reported by TorrentAddResult
+	source string
 }
 
+// ContentLayout layout of the content of an added torrent
+type ContentLayout string
+
+const (
+	ContentLayoutOriginal    ContentLayout = "Original"    // keep the layout of the torrent
+	ContentLayoutSubfolder   ContentLayout = "Subfolder"   // always create a root folder
+	ContentLayoutNoSubfolder ContentLayout = "NoSubfolder" // remove the root folder of the torrent
+)
+
+// StopCondition condition to stop an added torrent automatically
+type StopCondition string
+
+const (
+	StopConditionNone             StopCondition = "None"
+	StopConditionMetadataReceived StopCondition = "MetadataReceived" // stop after the metadata of a magnet link is received
+	StopConditionFilesChecked     StopCondition = "FilesChecked"     // stop after the files are checked
+)
+
+// TorrentAddOption options of AddNewTorrent, the fields are sent with the names of the server
+// version, such as stopped or paused, contentLayout or root_folder
 type TorrentAddOption struct {
-	URLs               []string                  `schema:"-"`                            // torrents url
-	Torrents           []*TorrentAddFileMetadata `schema:"-"`                            // raw data of torrent file
-	SavePath           string                    `schema:"save_path,omitempty"`          // download folder, optional
-	Cookies            string                    `schema:"cookie,omitempty"`             // cookie sent to download torrent file, optional
-	Category           string                    `schema:"category,omitempty"`           // category for the torrent, optional
-	Tags               []string                  `schema:"-"`                            // tags for the torrent, optional
-	SkipChecking       bool                      `schema:"skip_checking,omitempty"`      // skip hash checking, optional
-	Paused             bool                      `schema:"paused,omitempty"`             // add torrent in the pause state, optional
-	RootFolder         bool                      `schema:"root_folder,omitempty"`        // create the root folder, optional
-	Rename             string                    `schema:"rename,omitempty"`             // rename torrent, optional
-	UpLimit            int                       `schema:"upLimit,omitempty"`            // set torrent upload speed, Unit in bytes/second, optional
-	DlLimit            int                       `schema:"dlLimit,omitempty"`            // set torrent download speed, Unit in bytes/second, optional
-	RatioLimit         float64                   `schema:"ratioLimit,omitempty"`         // set torrent share ratio limit, optional
-	SeedingTimeLimit   int                       `schema:"seedingTimeLimit,omitempty"`   // set torrent seeding torrent limit, Unit in minutes, optional
-	AutoTMM            bool                      `schema:"autoTMM,omitempty"`            // whether Automatic Torrent Management should be used, optional
-	SequentialDownload string                    `schema:"sequentialDownload,omitempty"` // enable sequential download, optional
-	FirstLastPiecePrio string                    `schema:"firstLastPiecePrio,omitempty"` // prioritize download first last piece, optional
+	URLs                     []string                  `schema:"-"`                                  // torrents url
+	Torrents                 []*TorrentAddFileMetadata `schema:"-"`                                  // raw data of torrent file
+	SavePath                 string                    `schema:"savepath,omitempty"`                 // download folder, optional
+	DownloadPath             string                    `schema:"downloadPath,omitempty"`             // folder of the incomplete torrent, optional, qBittorrent 4.4+
+	UseDownloadPath          *bool                     `schema:"useDownloadPath,omitempty"`          // whether DownloadPath is used, nil uses the preference, qBittorrent 4.4+
+	Cookies                  string                    `schema:"cookie,omitempty"`                   // cookie sent to download torrent file, optional
+	Category                 string                    `schema:"category,omitempty"`                 // category for the torrent, optional
+	Tags                     []string                  `schema:"-"`                                  // tags for the torrent, optional
+	SkipChecking             bool                      `schema:"skip_checking,omitempty"`            // skip hash checking, optional
+	Stopped                  bool                      `schema:"stopped,omitempty"`                  // add torrent in the stopped state, sent as paused before qBittorrent 5.0, optional
+	Paused                   bool                      `schema:"paused,omitempty"`                   // Deprecated: use Stopped, both are sent with the name of the server version
+	Forced                   bool                      `schema:"forced,omitempty"`                   // add torrent in the forced state, optional, qBittorrent 5.0+
+	AddToTopOfQueue          *bool                     `schema:"addToTopOfQueue,omitempty"`          // add torrent to the top of the queue, nil uses the preference, qBittorrent 4.5+
+	StopCondition            StopCondition             `schema:"stopCondition,omitempty"`            // stop the torrent automatically, optional, qBittorrent 4.5+
+	ContentLayout            ContentLayout             `schema:"contentLayout,omitempty"`            // layout of the content, sent as root_folder before qBittorrent 4.3.2, optional
+	RootFolder               bool                      `schema:"root_folder,omitempty"`              // Deprecated: use ContentLayoutSubfolder, create the root folder
+	Rename                   string                    `schema:"rename,omitempty"`                   // rename torrent, optional
+	UpLimit                  int                       `schema:"upLimit,omitempty"`                  // set torrent upload speed, Unit in bytes/second, optional
+	DlLimit                  int                       `schema:"dlLimit,omitempty"`                  // set torrent download speed, Unit in bytes/second, optional
+	RatioLimit               float64                   `schema:"ratioLimit,omitempty"`               // set torrent share ratio limit, optional
+	SeedingTimeLimit         int                       `schema:"seedingTimeLimit,omitempty"`         // set torrent seeding torrent limit, Unit in minutes, optional
+	InactiveSeedingTimeLimit int                       `schema:"inactiveSeedingTimeLimit,omitempty"` // set torrent inactive seeding time limit, Unit in minutes, optional, qBittorrent 4.6+
+	AutoTMM                  bool                      `schema:"autoTMM,omitempty"`                  // whether Automatic Torrent Management should be used, optional
+	SequentialDownload       string                    `schema:"sequentialDownload,omitempty"`       // enable sequential download, optional
+	FirstLastPiecePrio       string                    `schema:"firstLastPiecePrio,omitempty"`       // prioritize download first last piece, optional
+}
+
+// formFields returns the form fields of the option except urls and torrents, the names of the
+// fields depend on the webapi version of the server
+func (opt *TorrentAddOption) formFields(version Version) url.Values {
+	var form = url.Values{}
+	var set = func(key, value string, ok bool) {
+		if ok {
+			form.Set(key, value)
+		}
+	}
+	set("savepath", opt.SavePath, opt.SavePath != "")
+	set("downloadPath", opt.DownloadPath, opt.DownloadPath != "")
+	if opt.UseDownloadPath != nil {
+		form.Set("useDownloadPath", strconv.FormatBool(*opt.UseDownloadPath))
+	}
+	set("cookie", opt.Cookies, opt.Cookies != "")
+	set("category", opt.Category, opt.Category != "")
+	set("tags", strings.Join(opt.Tags, ","), len(opt.Tags) != 0)
+	set("skip_checking", "true", opt.SkipChecking)
+	if opt.Stopped || opt.Paused {
+		if version.AtLeast(versionStopStart) {
+			form.Set("stopped", "true")
+		} else {
+			form.Set("paused", "true")
+		}
+	}
+	set("forced", "true", opt.Forced)
+	if opt.AddToTopOfQueue != nil {
+		form.Set("addToTopOfQueue", strconv.FormatBool(*opt.AddToTopOfQueue))
+	}
+	set("stopCondition", string(opt.StopCondition), opt.StopCondition != "")
+
+	var layout = opt.ContentLayout
+	if layout == "" && opt.RootFolder {
+		layout = ContentLayoutSubfolder
+	}
+	if version.AtLeast(versionContentLayout) {
+		set("contentLayout", string(layout), layout != "")
+	} else if layout == ContentLayoutSubfolder || layout == ContentLayoutNoSubfolder {
+		form.Set("root_folder", strconv.FormatBool(layout == ContentLayoutSubfolder))
+	}
+
+	set("rename", opt.Rename, opt.Rename != "")
+	set("upLimit", strconv.Itoa(opt.UpLimit), opt.UpLimit != 0)
+	set("dlLimit", strconv.Itoa(opt.DlLimit), opt.DlLimit != 0)
+	set("ratioLimit", strconv.FormatFloat(opt.RatioLimit, 'f', -1, 64), opt.RatioLimit != 0)
+	set("seedingTimeLimit", strconv.Itoa(opt.SeedingTimeLimit), opt.SeedingTimeLimit != 0)
+	set("inactiveSeedingTimeLimit", strconv.Itoa(opt.InactiveSeedingTimeLimit), opt.InactiveSeedingTimeLimit != 0)
+	set("autoTMM", "true", opt.AutoTMM)
+	set("sequentialDownload", opt.SequentialDownload, opt.SequentialDownload != "")
+	set("firstLastPiecePrio", opt.FirstLastPiecePrio, opt.FirstLastPiecePrio != "")
+	return form
 }
 
 type TorrentCategory struct {
@@ -292,6 +483,10 @@ type TorrentCategory struct {
 }
 
 func (c *client) GetTorrents(opt *TorrentOption) ([]*TorrentInfo, error) {
+	return c.GetTorrentsContext(context.Background(), opt)
+}
+
+func (c *client) GetTorrentsContext(ctx context.Context, opt *TorrentOption) ([]*TorrentInfo, error) {
 	var formData = url.Values{}
 	err := encoder.Encode(opt, formData)
 	if err != nil {
@@ -300,9 +495,17 @@ func (c *client) GetTorrents(opt *TorrentOption) ([]*TorrentInfo, error) {
 	if len(opt.Hashes) != 0 {
 		formData.Add("hashes", strings.Join(opt.Hashes, "|"))
 	}
+	switch opt.Filter {
+	case FilterPaused, FilterStopped, FilterResumed, FilterRunning:
+		version, err := c.webAPIVersion(ctx)
+		if err != nil {
+			return nil, err
+		}
+		formData.Set("filter", string(opt.Filter.forVersion(version)))
+	}
 
-	apiUrl := fmt.Sprintf("%s/api/v2/torrents/info?%s", c.config.Address, formData.Encode())
-	result, err := c.doRequest(&requestData{
+	apiUrl := c.apiURL("torrents/info", formData)
+	result, err := c.doRequest(ctx, &requestData{
 		url: apiUrl,
 	})
 	if err != nil {
@@ -310,11 +513,9 @@ func (c *client) GetTorrents(opt *TorrentOption) ([]*TorrentInfo, error) {
 	}
 
 	if result.code != 200 {
-		return nil, errors.New("get torrents failed: " + string(result.body))
+		return nil, newAPIError("get torrents failed", result)
 	}
 
-	fmt.Println(string(result.body))
-
 	var mainData []*TorrentInfo
 	if err := sonic.Unmarshal(result.body, &mainData); err != nil {
 		return nil, err
@@ -324,8 +525,12 @@ func (c *client) GetTorrents(opt *TorrentOption) ([]*TorrentInfo, error) {
 }
 
 func (c *client) GetProperties(hash string) (*TorrentProperties, error) {
-	apiUrl := fmt.Sprintf("%s/api/v2/torrents/properties?hash=%s", c.config.Address, hash)
-	result, err := c.doRequest(&requestData{
+	return c.GetPropertiesContext(context.Background(), hash)
+}
+
+func (c *client) GetPropertiesContext(ctx context.Context, hash string) (*TorrentProperties, error) {
+	apiUrl := c.apiURL("torrents/properties", url.Values{"hash": {hash}})
+	result, err := c.doRequest(ctx, &requestData{
 		url: apiUrl,
 	})
 	if err != nil {
@@ -333,7 +538,7 @@ func (c *client) GetProperties(hash string) (*TorrentProperties, error) {
 	}
 
 	if result.code != 200 {
-		return nil, errors.New("get torrent properties failed: " + string(result.body))
+		return nil, newAPIError("get torrent properties failed", result)
 	}
 
 	var mainData = new(TorrentProperties)
@@ -345,8 +550,12 @@ func (c *client) GetProperties(hash string) (*TorrentProperties, error) {
 }
 
 func (c *client) GetTrackers(hash string) ([]*TorrentTracker, error) {
-	apiUrl := fmt.Sprintf("%s/api/v2/torrents/trackers?hash=%s", c.config.Address, hash)
-	result, err := c.doRequest(&requestData{
+	return c.GetTrackersContext(context.Background(), hash)
+}
+
+func (c *client) GetTrackersContext(ctx context.Context, hash string) ([]*TorrentTracker, error) {
+	apiUrl := c.apiURL("torrents/trackers", url.Values{"hash": {hash}})
+	result, err := c.doRequest(ctx, &requestData{
 		url: apiUrl,
 	})
 	if err != nil {
@@ -354,7 +563,7 @@ func (c *client) GetTrackers(hash string) ([]*TorrentTracker, error) {
 	}
 
 	if result.code != 200 {
-		return nil, errors.New("get torrent trackers failed: " + string(result.body))
+		return nil, newAPIError("get torrent trackers failed", result)
 	}
 
 	var mainData []*TorrentTracker
@@ -366,8 +575,12 @@ func (c *client) GetTrackers(hash string) ([]*TorrentTracker, error) {
 }
 
 func (c *client) GetWebSeeds(hash string) ([]*TorrentWebSeed, error) {
-	apiUrl := fmt.Sprintf("%s/api/v2/torrents/webseeds?hash=%s", c.config.Address, hash)
-	result, err := c.doRequest(&requestData{
+	return c.GetWebSeedsContext(context.Background(), hash)
+}
+
+func (c *client) GetWebSeedsContext(ctx context.Context, hash string) ([]*TorrentWebSeed, error) {
+	apiUrl := c.apiURL("torrents/webseeds", url.Values{"hash": {hash}})
+	result, err := c.doRequest(ctx, &requestData{
 		url: apiUrl,
 	})
 	if err != nil {
@@ -375,7 +588,7 @@ func (c *client) GetWebSeeds(hash string) ([]*TorrentWebSeed, error) {
 	}
 
 	if result.code != 200 {
-		return nil, errors.New("get torrent web seeds failed: " + string(result.body))
+		return nil, newAPIError("get torrent web seeds failed", result)
 	}
 
 	var mainData []*TorrentWebSeed
@@ -387,13 +600,16 @@ func (c *client) GetWebSeeds(hash string) ([]*TorrentWebSeed, error) {
 }
 
 func (c *client) GetContents(hash string, indexes ...string) ([]*TorrentContent, error) {
-	var apiUrl string
+	return c.GetContentsContext(context.Background(), hash, indexes...)
+}
+
+func (c *client) GetContentsContext(ctx context.Context, hash string, indexes ...string) ([]*TorrentContent, error) {
+	var query = url.Values{"hash": {hash}}
 	if len(indexes) != 0 {
-		apiUrl = fmt.Sprintf("%s/api/v2/torrents/files?hash=%s&indexes=%s", c.config.Address, hash, strings.Join(indexes, "|"))
-	} else {
-		apiUrl = fmt.Sprintf("%s/api/v2/torrents/files?hash=%s", c.config.Address, hash)
+		query.Set("indexes", strings.Join(indexes, "|"))
 	}
-	result, err := c.doRequest(&requestData{
+	var apiUrl = c.apiURL("torrents/files", query)
+	result, err := c.doRequest(ctx, &requestData{
 		url: apiUrl,
 	})
 	if err != nil {
@@ -401,7 +617,7 @@ func (c *client) GetContents(hash string, indexes ...string) ([]*TorrentContent,
 	}
 
 	if result.code != 200 {
-		return nil, errors.New("get torrent web seeds failed: " + string(result.body))
+		return nil, newAPIError("get torrent web seeds failed", result)
 	}
 
 	var mainData []*TorrentContent
@@ -413,8 +629,12 @@ func (c *client) GetContents(hash string, indexes ...string) ([]*TorrentContent,
 }
 
 func (c *client) GetPiecesStates(hash string) ([]int, error) {
-	var apiUrl = fmt.Sprintf("%s/api/v2/torrents/pieceStates?hash=%s", c.config.Address, hash)
-	result, err := c.doRequest(&requestData{
+	return c.GetPiecesStatesContext(context.Background(), hash)
+}
+
+func (c *client) GetPiecesStatesContext(ctx context.Context, hash string) ([]int, error) {
+	var apiUrl = c.apiURL("torrents/pieceStates", url.Values{"hash": {hash}})
+	result, err := c.doRequest(ctx, &requestData{
 		url: apiUrl,
 	})
 	if err != nil {
@@ -422,7 +642,7 @@ func (c *client) GetPiecesStates(hash string) ([]int, error) {
 	}
 
 	if result.code != 200 {
-		return nil, errors.New("get torrent pieces states failed: " + string(result.body))
+		return nil, newAPIError("get torrent pieces states failed", result)
 	}
 
 	var mainData []int
@@ -434,8 +654,12 @@ func (c *client) GetPiecesStates(hash string) ([]int, error) {
 }
 
 func (c *client) GetPiecesHashes(hash string) ([]string, error) {
-	var apiUrl = fmt.Sprintf("%s/api/v2/torrents/pieceHashes?hash=%s", c.config.Address, hash)
-	result, err := c.doRequest(&requestData{
+	return c.GetPiecesHashesContext(context.Background(), hash)
+}
+
+func (c *client) GetPiecesHashesContext(ctx context.Context, hash string) ([]string, error) {
+	var apiUrl = c.apiURL("torrents/pieceHashes", url.Values{"hash": {hash}})
+	result, err := c.doRequest(ctx, &requestData{
 		url: apiUrl,
 	})
 	if err != nil {
@@ -443,7 +667,7 @@ func (c *client) GetPiecesHashes(hash string) ([]string, error) {
 	}
 
 	if result.code != 200 {
-		return nil, errors.New("get torrent pieces states failed: " + string(result.body))
+		return nil, newAPIError("get torrent pieces states failed", result)
 	}
 
 	var mainData []string
@@ -455,13 +679,22 @@ func (c *client) GetPiecesHashes(hash string) ([]string, error) {
 }
 
 func (c *client) PauseTorrents(hashes []string) error {
+	return c.PauseTorrentsContext(context.Background(), hashes)
+}
+
+func (c *client) PauseTorrentsContext(ctx context.Context, hashes []string) error {
 	if len(hashes) == 0 {
 		return errors.New("no torrent hashes provided")
 	}
 	var formData = url.Values{}
 	formData.Add("hashes", strings.Join(hashes, "|"))
-	var apiUrl = fmt.Sprintf("%s/api/v2/torrents/pause", c.config.Address)
-	result, err := c.doRequest(&requestData{
+	// qBittorrent 5.0 renamed torrents/pause to torrents/stop
+	endpoint, err := c.endpointByVersion(ctx, versionStopStart, "stop", "pause")
+	if err != nil {
+		return err
+	}
+	var apiUrl = c.apiURL("torrents/"+endpoint, nil)
+	result, err := c.doRequest(ctx, &requestData{
 		url:    apiUrl,
 		method: http.MethodPost,
 		body:   strings.NewReader(formData.Encode()),
@@ -471,19 +704,28 @@ func (c *client) PauseTorrents(hashes []string) error {
 	}
 
 	if result.code != 200 {
-		return errors.New("pause torrents failed: " + string(result.body))
+		return newAPIError("pause torrents failed", result)
 	}
 	return nil
 }
 
 func (c *client) ResumeTorrents(hashes []string) error {
+	return c.ResumeTorrentsContext(context.Background(), hashes)
+}
+
+func (c *client) ResumeTorrentsContext(ctx context.Context, hashes []string) error {
 	if len(hashes) == 0 {
 		return errors.New("no torrent hashes provided")
 	}
 	var formData = url.Values{}
 	formData.Add("hashes", strings.Join(hashes, "|"))
-	var apiUrl = fmt.Sprintf("%s/api/v2/torrents/resume", c.config.Address)
-	result, err := c.doRequest(&requestData{
+	// qBittorrent 5.0 renamed torrents/resume to torrents/start
+	endpoint, err := c.endpointByVersion(ctx, versionStopStart, "start", "resume")
+	if err != nil {
+		return err
+	}
+	var apiUrl = c.apiURL("torrents/"+endpoint, nil)
+	result, err := c.doRequest(ctx, &requestData{
 		url:    apiUrl,
 		method: http.MethodPost,
 		body:   strings.NewReader(formData.Encode()),
@@ -493,20 +735,24 @@ func (c *client) ResumeTorrents(hashes []string) error {
 	}
 
 	if result.code != 200 {
-		return errors.New("resume torrents failed: " + string(result.body))
+		return newAPIError("resume torrents failed", result)
 	}
 	return nil
 }
 
 func (c *client) DeleteTorrents(hashes []string, deleteFile bool) error {
+	return c.DeleteTorrentsContext(context.Background(), hashes, deleteFile)
+}
+
+func (c *client) DeleteTorrentsContext(ctx context.Context, hashes []string, deleteFile bool) error {
 	if len(hashes) == 0 {
 		return errors.New("no torrent hashes provided")
 	}
 	var formData = url.Values{}
 	formData.Add("hashes", strings.Join(hashes, "|"))
-	formData.Add("deleteFile", strconv.FormatBool(deleteFile))
-	var apiUrl = fmt.Sprintf("%s/api/v2/torrents/resume", c.config.Address)
-	result, err := c.doRequest(&requestData{
+	formData.Add("deleteFiles", strconv.FormatBool(deleteFile))
+	var apiUrl = c.apiURL("torrents/delete", nil)
+	result, err := c.doRequest(ctx, &requestData{
 		url:    apiUrl,
 		method: http.MethodPost,
 		body:   strings.NewReader(formData.Encode()),
@@ -516,19 +762,23 @@ func (c *client) DeleteTorrents(hashes []string, deleteFile bool) error {
 	}
 
 	if result.code != 200 {
-		return errors.New("delete torrents failed: " + string(result.body))
+		return newAPIError("delete torrents failed", result)
 	}
 	return nil
 }
 
 func (c *client) RecheckTorrents(hashes []string) error {
+	return c.RecheckTorrentsContext(context.Background(), hashes)
+}
+
+func (c *client) RecheckTorrentsContext(ctx context.Context, hashes []string) error {
 	if len(hashes) == 0 {
 		return errors.New("no torrent hashes provided")
 	}
 	var formData = url.Values{}
 	formData.Add("hashes", strings.Join(hashes, "|"))
-	var apiUrl = fmt.Sprintf("%s/api/v2/torrents/recheck", c.config.Address)
-	result, err := c.doRequest(&requestData{
+	var apiUrl = c.apiURL("torrents/recheck", nil)
+	result, err := c.doRequest(ctx, &requestData{
 		url:    apiUrl,
 		method: http.MethodPost,
 		body:   strings.NewReader(formData.Encode()),
@@ -538,19 +788,23 @@ func (c *client) RecheckTorrents(hashes []string) error {
 	}
 
 	if result.code != 200 {
-		return errors.New("recheck torrents failed: " + string(result.body))
+		return newAPIError("recheck torrents failed", result)
 	}
 	return nil
 }
 
 func (c *client) ReAnnounceTorrents(hashes []string) error {
+	return c.ReAnnounceTorrentsContext(context.Background(), hashes)
+}
+
+func (c *client) ReAnnounceTorrentsContext(ctx context.Context, hashes []string) error {
 	if len(hashes) == 0 {
 		return errors.New("no torrent hashes provided")
 	}
 	var formData = url.Values{}
 	formData.Add("hashes", strings.Join(hashes, "|"))
-	var apiUrl = fmt.Sprintf("%s/api/v2/torrents/reannounce", c.config.Address)
-	result, err := c.doRequest(&requestData{
+	var apiUrl = c.apiURL("torrents/reannounce", nil)
+	result, err := c.doRequest(ctx, &requestData{
 		url:    apiUrl,
 		method: http.MethodPost,
 		body:   strings.NewReader(formData.Encode()),
@@ -560,108 +814,59 @@ func (c *client) ReAnnounceTorrents(hashes []string) error {
 	}
 
 	if result.code != 200 {
-		return errors.New("reannounce torrents failed: " + string(result.body))
+		return newAPIError("reannounce torrents failed", result)
 	}
 	return nil
 }
 
-func (c *client) AddNewTorrent(opt *TorrentAddOption) error {
-	var requestBody bytes.Buffer
-	var writer = multipart.NewWriter(&requestBody)
+func (c *client) AddNewTorrent(opt *TorrentAddOption) ([]string, error) {
+	return c.AddNewTorrentContext(context.Background(), opt)
+}
 
+func (c *client) AddNewTorrentContext(ctx context.Context, opt *TorrentAddOption) ([]string, error) {
 	if len(opt.URLs) == 0 && len(opt.Torrents) == 0 {
-		return errors.New("no torrent url or data provided")
+		return nil, errors.New("no torrent url or data provided")
 	}
 
-	if opt.SavePath != "" {
-		_ = writer.WriteField("savePath", opt.SavePath)
-	}
-	if opt.Cookies != "" {
-		_ = writer.WriteField("cookies", opt.Cookies)
-	}
-	if opt.Category != "" {
-		_ = writer.WriteField("category", opt.Category)
-	}
-	if len(opt.Tags) != 0 {
-		_ = writer.WriteField("tags", strings.Join(opt.Tags, ","))
-	}
-	if opt.SkipChecking {
-		_ = writer.WriteField("skip_checking", "true")
-	}
-	if opt.Paused {
-		_ = writer.WriteField("paused", "true")
-	}
-	if opt.RootFolder {
-		_ = writer.WriteField("root_folder", "true")
-	}
-	if opt.Rename != "" {
-		_ = writer.WriteField("rename", opt.Rename)
-	}
-	if opt.UpLimit != 0 {
-		_ = writer.WriteField("upLimit", strconv.Itoa(opt.UpLimit))
-	}
-	if opt.DlLimit != 0 {
-		_ = writer.WriteField("dlLimit", strconv.Itoa(opt.DlLimit))
-	}
-	if opt.RatioLimit != 0 {
-		_ = writer.WriteField("ratioLimit", strconv.FormatFloat(opt.RatioLimit, 'f', -1, 64))
-	}
-	if opt.SeedingTimeLimit != 0 {
-		_ = writer.WriteField("seedingTimeLimit", strconv.Itoa(opt.SeedingTimeLimit))
-	}
-	if opt.AutoTMM {
-		_ = writer.WriteField("autoTMM", "true")
-	}
-	if opt.SequentialDownload != "" {
-		_ = writer.WriteField("sequentialDownload", opt.SequentialDownload)
-	}
-	if opt.FirstLastPiecePrio != "" {
-		_ = writer.WriteField("firstLastPiecePrio", opt.FirstLastPiecePrio)
+	version, err := c.webAPIVersion(ctx)
+	if err != nil {
+		return nil, err
 	}
-
-	if len(opt.URLs) != 0 {
-		_ = writer.WriteField("urls", strings.Join(opt.URLs, "\n"))
-	} else if len(opt.Torrents) != 0 {
-		for _, torrent := range opt.Torrents {
-			formFile, err := writer.CreateFormFile("torrents", torrent.Filename)
-			if err != nil {
-				return err
-			}
-			_, err = io.Copy(formFile, bytes.NewReader(torrent.Data))
-			if err != nil {
-				return err
-			}
+	var hashes []string
+	var upload = newTorrentUpload(opt.formFields(version))
+	upload.urls = opt.URLs
+	for _, u := range opt.URLs {
+		if magnet, err := ParseMagnet(u); err == nil {
+			hashes = append(hashes, magnet.Hash())
 		}
 	}
-	_ = writer.Close()
-
-	var apiUrl = fmt.Sprintf("%s/api/v2/torrents/add", c.config.Address)
-	result, err := c.doRequest(&requestData{
-		url:         apiUrl,
-		method:      http.MethodPost,
-		contentType: writer.FormDataContentType(),
-		body:        &requestBody,
-		debug:       true,
-	})
-	if err != nil {
-		return err
+	for _, torrent := range opt.Torrents {
+		hash, err := upload.addFile(torrent)
+		if err != nil {
+			return nil, err
+		}
+		hashes = append(hashes, hash)
 	}
 
-	if result.code != 200 {
-		return errors.New("add torrents failed: " + string(result.body))
+	if err := c.uploadTorrents(ctx, upload); err != nil {
+		return nil, err
 	}
-	return nil
+	return hashes, nil
 }
 
 func (c *client) AddTrackers(hash string, urls []string) error {
+	return c.AddTrackersContext(context.Background(), hash, urls)
+}
+
+func (c *client) AddTrackersContext(ctx context.Context, hash string, urls []string) error {
 	if len(urls) == 0 {
 		return errors.New("no torrent tracker provided")
 	}
 	var formData = url.Values{}
-	formData.Add("urls", strings.Join(urls, "%0A"))
+	formData.Add("urls", strings.Join(urls, "\n"))
 	formData.Add("hash", hash)
-	var apiUrl = fmt.Sprintf("%s/api/v2/torrents/addTrackers", c.config.Address)
-	result, err := c.doRequest(&requestData{
+	var apiUrl = c.apiURL("torrents/addTrackers", nil)
+	result, err := c.doRequest(ctx, &requestData{
 		url:    apiUrl,
 		method: http.MethodPost,
 		body:   strings.NewReader(formData.Encode()),
@@ -671,18 +876,22 @@ func (c *client) AddTrackers(hash string, urls []string) error {
 	}
 
 	if result.code != 200 {
-		return errors.New("add torrent trackers failed: " + string(result.body))
+		return newAPIError("add torrent trackers failed", result)
 	}
 	return nil
 }
 
 func (c *client) EditTrackers(hash, origUrl, newUrl string) error {
+	return c.EditTrackersContext(context.Background(), hash, origUrl, newUrl)
+}
+
+func (c *client) EditTrackersContext(ctx context.Context, hash, origUrl, newUrl string) error {
 	var formData = url.Values{}
 	formData.Add("origUrl", origUrl)
 	formData.Add("newUrl", newUrl)
 	formData.Add("hash", hash)
-	var apiUrl = fmt.Sprintf("%s/api/v2/torrents/editTracker", c.config.Address)
-	result, err := c.doRequest(&requestData{
+	var apiUrl = c.apiURL("torrents/editTracker", nil)
+	result, err := c.doRequest(ctx, &requestData{
 		url:    apiUrl,
 		method: http.MethodPost,
 		body:   strings.NewReader(formData.Encode()),
@@ -692,20 +901,24 @@ func (c *client) EditTrackers(hash, origUrl, newUrl string) error {
 	}
 
 	if result.code != 200 {
-		return errors.New("edit torrent trackers failed: " + string(result.body))
+		return newAPIError("edit torrent trackers failed", result)
 	}
 	return nil
 }
 
 func (c *client) RemoveTrackers(hash string, urls []string) error {
+	return c.RemoveTrackersContext(context.Background(), hash, urls)
+}
+
+func (c *client) RemoveTrackersContext(ctx context.Context, hash string, urls []string) error {
 	if len(urls) == 0 {
 		return errors.New("no torrent tracker provided")
 	}
 	var formData = url.Values{}
 	formData.Add("hash", hash)
 	formData.Add("urls", strings.Join(urls, "|"))
-	var apiUrl = fmt.Sprintf("%s/api/v2/torrents/removeTrackers", c.config.Address)
-	result, err := c.doRequest(&requestData{
+	var apiUrl = c.apiURL("torrents/removeTrackers", nil)
+	result, err := c.doRequest(ctx, &requestData{
 		url:    apiUrl,
 		method: http.MethodPost,
 		body:   strings.NewReader(formData.Encode()),
@@ -715,12 +928,16 @@ func (c *client) RemoveTrackers(hash string, urls []string) error {
 	}
 
 	if result.code != 200 {
-		return errors.New("remove torrent trackers failed: " + string(result.body))
+		return newAPIError("remove torrent trackers failed", result)
 	}
 	return nil
 }
 
 func (c *client) AddPeers(hashes []string, peers []string) error {
+	return c.AddPeersContext(context.Background(), hashes, peers)
+}
+
+func (c *client) AddPeersContext(ctx context.Context, hashes []string, peers []string) error {
 	if len(hashes) == 0 {
 		return errors.New("no hashes provided")
 	}
@@ -730,8 +947,8 @@ func (c *client) AddPeers(hashes []string, peers []string) error {
 	var formData = url.Values{}
 	formData.Add("hashes", strings.Join(hashes, "|"))
 	formData.Add("peers", strings.Join(peers, "|"))
-	var apiUrl = fmt.Sprintf("%s/api/v2/torrents/addPeers", c.config.Address)
-	result, err := c.doRequest(&requestData{
+	var apiUrl = c.apiURL("torrents/addPeers", nil)
+	result, err := c.doRequest(ctx, &requestData{
 		url:    apiUrl,
 		method: http.MethodPost,
 		body:   strings.NewReader(formData.Encode()),
@@ -741,19 +958,23 @@ func (c *client) AddPeers(hashes []string, peers []string) error {
 	}
 
 	if result.code != 200 {
-		return errors.New("addPeers torrents failed: " + string(result.body))
+		return newAPIError("addPeers torrents failed", result)
 	}
 	return nil
 }
 
 func (c *client) IncreasePriority(hashes []string) error {
+	return c.IncreasePriorityContext(context.Background(), hashes)
+}
+
+func (c *client) IncreasePriorityContext(ctx context.Context, hashes []string) error {
 	if len(hashes) == 0 {
 		return errors.New("no hashes provided")
 	}
 	var formData = url.Values{}
 	formData.Add("hashes", strings.Join(hashes, "|"))
-	var apiUrl = fmt.Sprintf("%s/api/v2/torrents/increasePrio", c.config.Address)
-	result, err := c.doRequest(&requestData{
+	var apiUrl = c.apiURL("torrents/increasePrio", nil)
+	result, err := c.doRequest(ctx, &requestData{
 		url:    apiUrl,
 		method: http.MethodPost,
 		body:   strings.NewReader(formData.Encode()),
@@ -763,19 +984,23 @@ func (c *client) IncreasePriority(hashes []string) error {
 	}
 
 	if result.code != 200 {
-		return errors.New("increasePrio torrents failed: " + string(result.body))
+		return newAPIError("increasePrio torrents failed", result)
 	}
 	return nil
 }
 
 func (c *client) DecreasePriority(hashes []string) error {
+	return c.DecreasePriorityContext(context.Background(), hashes)
+}
+
+func (c *client) DecreasePriorityContext(ctx context.Context, hashes []string) error {
 	if len(hashes) == 0 {
 		return errors.New("no hashes provided")
 	}
 	var formData = url.Values{}
 	formData.Add("hashes", strings.Join(hashes, "|"))
-	var apiUrl = fmt.Sprintf("%s/api/v2/torrents/decreasePrio", c.config.Address)
-	result, err := c.doRequest(&requestData{
+	var apiUrl = c.apiURL("torrents/decreasePrio", nil)
+	result, err := c.doRequest(ctx, &requestData{
 		url:    apiUrl,
 		method: http.MethodPost,
 		body:   strings.NewReader(formData.Encode()),
@@ -785,19 +1010,23 @@ func (c *client) DecreasePriority(hashes []string) error {
 	}
 
 	if result.code != 200 {
-		return errors.New("decreasePrio torrents failed: " + string(result.body))
+		return newAPIError("decreasePrio torrents failed", result)
 	}
 	return nil
 }
 
 func (c *client) MaxPriority(hashes []string) error {
+	return c.MaxPriorityContext(context.Background(), hashes)
+}
+
+func (c *client) MaxPriorityContext(ctx context.Context, hashes []string) error {
 	if len(hashes) == 0 {
 		return errors.New("no hashes provided")
 	}
 	var formData = url.Values{}
 	formData.Add("hashes", strings.Join(hashes, "|"))
-	var apiUrl = fmt.Sprintf("%s/api/v2/torrents/topPrio", c.config.Address)
-	result, err := c.doRequest(&requestData{
+	var apiUrl = c.apiURL("torrents/topPrio", nil)
+	result, err := c.doRequest(ctx, &requestData{
 		url:    apiUrl,
 		method: http.MethodPost,
 		body:   strings.NewReader(formData.Encode()),
@@ -807,19 +1036,23 @@ func (c *client) MaxPriority(hashes []string) error {
 	}
 
 	if result.code != 200 {
-		return errors.New("topPrio torrents failed: " + string(result.body))
+		return newAPIError("topPrio torrents failed", result)
 	}
 	return nil
 }
 
 func (c *client) MinPriority(hashes []string) error {
+	return c.MinPriorityContext(context.Background(), hashes)
+}
+
+func (c *client) MinPriorityContext(ctx context.Context, hashes []string) error {
 	if len(hashes) == 0 {
 		return errors.New("no hashes provided")
 	}
 	var formData = url.Values{}
 	formData.Add("hashes", strings.Join(hashes, "|"))
-	var apiUrl = fmt.Sprintf("%s/api/v2/torrents/bottomPrio", c.config.Address)
-	result, err := c.doRequest(&requestData{
+	var apiUrl = c.apiURL("torrents/bottomPrio", nil)
+	result, err := c.doRequest(ctx, &requestData{
 		url:    apiUrl,
 		method: http.MethodPost,
 		body:   strings.NewReader(formData.Encode()),
@@ -829,18 +1062,22 @@ func (c *client) MinPriority(hashes []string) error {
 	}
 
 	if result.code != 200 {
-		return errors.New("bottomPrio torrents failed: " + string(result.body))
+		return newAPIError("bottomPrio torrents failed", result)
 	}
 	return nil
 }
 
 func (c *client) SetFilePriority(hash string, id string, priority int) error {
+	return c.SetFilePriorityContext(context.Background(), hash, id, priority)
+}
+
+func (c *client) SetFilePriorityContext(ctx context.Context, hash string, id string, priority int) error {
 	var formData = url.Values{}
 	formData.Add("hash", hash)
 	formData.Add("id", id)
 	formData.Add("priority", strconv.Itoa(priority))
-	var apiUrl = fmt.Sprintf("%s/api/v2/torrents/filePrio", c.config.Address)
-	result, err := c.doRequest(&requestData{
+	var apiUrl = c.apiURL("torrents/filePrio", nil)
+	result, err := c.doRequest(ctx, &requestData{
 		url:    apiUrl,
 		method: http.MethodPost,
 		body:   strings.NewReader(formData.Encode()),
@@ -850,19 +1087,23 @@ func (c *client) SetFilePriority(hash string, id string, priority int) error {
 	}
 
 	if result.code != 200 {
-		return errors.New("filePrio torrents failed: " + string(result.body))
+		return newAPIError("filePrio torrents failed", result)
 	}
 	return nil
 }
 
 func (c *client) GetDownloadLimit(hashes []string) (map[string]int, error) {
+	return c.GetDownloadLimitContext(context.Background(), hashes)
+}
+
+func (c *client) GetDownloadLimitContext(ctx context.Context, hashes []string) (map[string]int, error) {
 	if len(hashes) == 0 {
 		return nil, errors.New("no hashes provided")
 	}
 	var formData = url.Values{}
 	formData.Add("hashes", strings.Join(hashes, "|"))
-	var apiUrl = fmt.Sprintf("%s/api/v2/torrents/downloadLimit", c.config.Address)
-	result, err := c.doRequest(&requestData{
+	var apiUrl = c.apiURL("torrents/downloadLimit", nil)
+	result, err := c.doRequest(ctx, &requestData{
 		url:    apiUrl,
 		method: http.MethodPost,
 		body:   strings.NewReader(formData.Encode()),
@@ -872,7 +1113,7 @@ func (c *client) GetDownloadLimit(hashes []string) (map[string]int, error) {
 	}
 
 	if result.code != 200 {
-		return nil, errors.New("get torrents download limit failed: " + string(result.body))
+		return nil, newAPIError("get torrents download limit failed", result)
 	}
 	var data = make(map[string]int)
 	err = sonic.Unmarshal(result.body, &data)
@@ -880,14 +1121,18 @@ func (c *client) GetDownloadLimit(hashes []string) (map[string]int, error) {
 }
 
 func (c *client) SetDownloadLimit(hashes []string, limit int) error {
+	return c.SetDownloadLimitContext(context.Background(), hashes, limit)
+}
+
+func (c *client) SetDownloadLimitContext(ctx context.Context, hashes []string, limit int) error {
 	if len(hashes) == 0 {
 		return errors.New("no hashes provided")
 	}
 	var formData = url.Values{}
 	formData.Add("hashes", strings.Join(hashes, "|"))
 	formData.Add("limit", strconv.Itoa(limit))
-	var apiUrl = fmt.Sprintf("%s/api/v2/torrents/setDownloadLimit", c.config.Address)
-	result, err := c.doRequest(&requestData{
+	var apiUrl = c.apiURL("torrents/setDownloadLimit", nil)
+	result, err := c.doRequest(ctx, &requestData{
 		url:    apiUrl,
 		method: http.MethodPost,
 		body:   strings.NewReader(formData.Encode()),
@@ -897,12 +1142,16 @@ func (c *client) SetDownloadLimit(hashes []string, limit int) error {
 	}
 
 	if result.code != 200 {
-		return errors.New("set torrents download limit failed: " + string(result.body))
+		return newAPIError("set torrents download limit failed", result)
 	}
 	return err
 }
 
 func (c *client) SetShareLimit(hashes []string, ratioLimit float64, seedingTimeLimit, inactiveSeedingTimeLimit int) error {
+	return c.SetShareLimitContext(context.Background(), hashes, ratioLimit, seedingTimeLimit, inactiveSeedingTimeLimit)
+}
+
+func (c *client) SetShareLimitContext(ctx context.Context, hashes []string, ratioLimit float64, seedingTimeLimit, inactiveSeedingTimeLimit int) error {
 	if len(hashes) == 0 {
 		return errors.New("no hashes provided")
 	}
@@ -911,8 +1160,8 @@ func (c *client) SetShareLimit(hashes []string, ratioLimit float64, seedingTimeL
 	formData.Add("ratioLimit", strconv.FormatFloat(ratioLimit, 'f', -1, 64))
 	formData.Add("seedingTimeLimit", strconv.Itoa(seedingTimeLimit))
 	formData.Add("inactiveSeedingTimeLimit", strconv.Itoa(inactiveSeedingTimeLimit))
-	var apiUrl = fmt.Sprintf("%s/api/v2/torrents/setShareLimits", c.config.Address)
-	result, err := c.doRequest(&requestData{
+	var apiUrl = c.apiURL("torrents/setShareLimits", nil)
+	result, err := c.doRequest(ctx, &requestData{
 		url:    apiUrl,
 		method: http.MethodPost,
 		body:   strings.NewReader(formData.Encode()),
@@ -922,19 +1171,23 @@ func (c *client) SetShareLimit(hashes []string, ratioLimit float64, seedingTimeL
 	}
 
 	if result.code != 200 {
-		return errors.New("set torrents share limit failed: " + string(result.body))
+		return newAPIError("set torrents share limit failed", result)
 	}
 	return err
 }
 
 func (c *client) GetUploadLimit(hashes []string) (map[string]int, error) {
+	return c.GetUploadLimitContext(context.Background(), hashes)
+}
+
+func (c *client) GetUploadLimitContext(ctx context.Context, hashes []string) (map[string]int, error) {
 	if len(hashes) == 0 {
 		return nil, errors.New("no hashes provided")
 	}
 	var formData = url.Values{}
 	formData.Add("hashes", strings.Join(hashes, "|"))
-	var apiUrl = fmt.Sprintf("%s/api/v2/torrents/uploadLimit", c.config.Address)
-	result, err := c.doRequest(&requestData{
+	var apiUrl = c.apiURL("torrents/uploadLimit", nil)
+	result, err := c.doRequest(ctx, &requestData{
 		url:    apiUrl,
 		method: http.MethodPost,
 		body:   strings.NewReader(formData.Encode()),
@@ -944,7 +1197,7 @@ func (c *client) GetUploadLimit(hashes []string) (map[string]int, error) {
 	}
 
 	if result.code != 200 {
-		return nil, errors.New("get torrents upload limit failed: " + string(result.body))
+		return nil, newAPIError("get torrents upload limit failed", result)
 	}
 	var data = make(map[string]int)
 	err = sonic.Unmarshal(result.body, &data)
@@ -952,14 +1205,18 @@ func (c *client) GetUploadLimit(hashes []string) (map[string]int, error) {
 }
 
 func (c *client) SetUploadLimit(hashes []string, limit int) error {
+	return c.SetUploadLimitContext(context.Background(), hashes, limit)
+}
+
+func (c *client) SetUploadLimitContext(ctx context.Context, hashes []string, limit int) error {
 	if len(hashes) == 0 {
 		return errors.New("no hashes provided")
 	}
 	var formData = url.Values{}
 	formData.Add("hashes", strings.Join(hashes, "|"))
 	formData.Add("limit", strconv.Itoa(limit))
-	var apiUrl = fmt.Sprintf("%s/api/v2/torrents/setUploadLimit", c.config.Address)
-	result, err := c.doRequest(&requestData{
+	var apiUrl = c.apiURL("torrents/setUploadLimit", nil)
+	result, err := c.doRequest(ctx, &requestData{
 		url:    apiUrl,
 		method: http.MethodPost,
 		body:   strings.NewReader(formData.Encode()),
@@ -969,20 +1226,24 @@ func (c *client) SetUploadLimit(hashes []string, limit int) error {
 	}
 
 	if result.code != 200 {
-		return errors.New("set torrents upload limit failed: " + string(result.body))
+		return newAPIError("set torrents upload limit failed", result)
 	}
 	return err
 }
 
 func (c *client) SetLocation(hashes []string, location string) error {
+	return c.SetLocationContext(context.Background(), hashes, location)
+}
+
+func (c *client) SetLocationContext(ctx context.Context, hashes []string, location string) error {
 	if len(hashes) == 0 {
 		return errors.New("no hashes provided")
 	}
 	var formData = url.Values{}
 	formData.Add("hashes", strings.Join(hashes, "|"))
 	formData.Add("location", location)
-	var apiUrl = fmt.Sprintf("%s/api/v2/torrents/setLocation", c.config.Address)
-	result, err := c.doRequest(&requestData{
+	var apiUrl = c.apiURL("torrents/setLocation", nil)
+	result, err := c.doRequest(ctx, &requestData{
 		url:    apiUrl,
 		method: http.MethodPost,
 		body:   strings.NewReader(formData.Encode()),
@@ -992,17 +1253,21 @@ func (c *client) SetLocation(hashes []string, location string) error {
 	}
 
 	if result.code != 200 {
-		return errors.New("set torrents location failed: " + string(result.body))
+		return newAPIError("set torrents location failed", result)
 	}
 	return err
 }
 
 func (c *client) SetName(hash string, name string) error {
+	return c.SetNameContext(context.Background(), hash, name)
+}
+
+func (c *client) SetNameContext(ctx context.Context, hash string, name string) error {
 	var formData = url.Values{}
 	formData.Add("hash", hash)
 	formData.Add("name", name)
-	var apiUrl = fmt.Sprintf("%s/api/v2/torrents/rename", c.config.Address)
-	result, err := c.doRequest(&requestData{
+	var apiUrl = c.apiURL("torrents/rename", nil)
+	result, err := c.doRequest(ctx, &requestData{
 		url:    apiUrl,
 		method: http.MethodPost,
 		body:   strings.NewReader(formData.Encode()),
@@ -1012,20 +1277,24 @@ func (c *client) SetName(hash string, name string) error {
 	}
 
 	if result.code != 200 {
-		return errors.New("set torrents name failed: " + string(result.body))
+		return newAPIError("set torrents name failed", result)
 	}
 	return err
 }
 
 func (c *client) SetCategory(hashes []string, category string) error {
+	return c.SetCategoryContext(context.Background(), hashes, category)
+}
+
+func (c *client) SetCategoryContext(ctx context.Context, hashes []string, category string) error {
 	if len(hashes) == 0 {
 		return errors.New("no hashes provided")
 	}
 	var formData = url.Values{}
 	formData.Add("hashes", strings.Join(hashes, "|"))
 	formData.Add("category", category)
-	var apiUrl = fmt.Sprintf("%s/api/v2/torrents/setCategory", c.config.Address)
-	result, err := c.doRequest(&requestData{
+	var apiUrl = c.apiURL("torrents/setCategory", nil)
+	result, err := c.doRequest(ctx, &requestData{
 		url:    apiUrl,
 		method: http.MethodPost,
 		body:   strings.NewReader(formData.Encode()),
@@ -1035,14 +1304,18 @@ func (c *client) SetCategory(hashes []string, category string) error {
 	}
 
 	if result.code != 200 {
-		return errors.New("set torrents category failed: " + string(result.body))
+		return newAPIError("set torrents category failed", result)
 	}
 	return err
 }
 
 func (c *client) GetCategories() (map[string]*TorrentCategory, error) {
-	var apiUrl = fmt.Sprintf("%s/api/v2/torrents/categories", c.config.Address)
-	result, err := c.doRequest(&requestData{
+	return c.GetCategoriesContext(context.Background())
+}
+
+func (c *client) GetCategoriesContext(ctx context.Context) (map[string]*TorrentCategory, error) {
+	var apiUrl = c.apiURL("torrents/categories", nil)
+	result, err := c.doRequest(ctx, &requestData{
 		url:    apiUrl,
 		method: http.MethodPost,
 	})
@@ -1051,7 +1324,7 @@ func (c *client) GetCategories() (map[string]*TorrentCategory, error) {
 	}
 
 	if result.code != 200 {
-		return nil, errors.New("get torrents upload limit failed: " + string(result.body))
+		return nil, newAPIError("get torrents upload limit failed", result)
 	}
 	var data = make(map[string]*TorrentCategory)
 	err = sonic.Unmarshal(result.body, &data)
@@ -1059,11 +1332,15 @@ func (c *client) GetCategories() (map[string]*TorrentCategory, error) {
 }
 
 func (c *client) AddNewCategory(category, savePath string) error {
+	return c.AddNewCategoryContext(context.Background(), category, savePath)
+}
+
+func (c *client) AddNewCategoryContext(ctx context.Context, category, savePath string) error {
 	var formData = url.Values{}
 	formData.Add("category", category)
 	formData.Add("savePath", savePath)
-	var apiUrl = fmt.Sprintf("%s/api/v2/torrents/createCategory", c.config.Address)
-	result, err := c.doRequest(&requestData{
+	var apiUrl = c.apiURL("torrents/createCategory", nil)
+	result, err := c.doRequest(ctx, &requestData{
 		url:    apiUrl,
 		method: http.MethodPost,
 		body:   strings.NewReader(formData.Encode()),
@@ -1073,17 +1350,21 @@ func (c *client) AddNewCategory(category, savePath string) error {
 	}
 
 	if result.code != 200 {
-		return errors.New("add new category failed: " + string(result.body))
+		return newAPIError("add new category failed", result)
 	}
 	return err
 }
 
 func (c *client) EditCategory(category, savePath string) error {
+	return c.EditCategoryContext(context.Background(), category, savePath)
+}
+
+func (c *client) EditCategoryContext(ctx context.Context, category, savePath string) error {
 	var formData = url.Values{}
 	formData.Add("category", category)
 	formData.Add("savePath", savePath)
-	var apiUrl = fmt.Sprintf("%s/api/v2/torrents/editCategory", c.config.Address)
-	result, err := c.doRequest(&requestData{
+	var apiUrl = c.apiURL("torrents/editCategory", nil)
+	result, err := c.doRequest(ctx, &requestData{
 		url:    apiUrl,
 		method: http.MethodPost,
 		body:   strings.NewReader(formData.Encode()),
@@ -1093,16 +1374,20 @@ func (c *client) EditCategory(category, savePath string) error {
 	}
 
 	if result.code != 200 {
-		return errors.New("add new category failed: " + string(result.body))
+		return newAPIError("add new category failed", result)
 	}
 	return err
 }
 
 func (c *client) RemoveCategories(categories []string) error {
+	return c.RemoveCategoriesContext(context.Background(), categories)
+}
+
+func (c *client) RemoveCategoriesContext(ctx context.Context, categories []string) error {
 	var formData = url.Values{}
 	formData.Add("categories", strings.Join(categories, "\n"))
-	var apiUrl = fmt.Sprintf("%s/api/v2/torrents/removeCategories", c.config.Address)
-	result, err := c.doRequest(&requestData{
+	var apiUrl = c.apiURL("torrents/removeCategories", nil)
+	result, err := c.doRequest(ctx, &requestData{
 		url:    apiUrl,
 		method: http.MethodPost,
 		body:   strings.NewReader(formData.Encode()),
@@ -1112,17 +1397,21 @@ func (c *client) RemoveCategories(categories []string) error {
 	}
 
 	if result.code != 200 {
-		return errors.New("remove categories failed: " + string(result.body))
+		return newAPIError("remove categories failed", result)
 	}
 	return err
 }
 
 func (c *client) AddTags(hashes []string, tags []string) error {
+	return c.AddTagsContext(context.Background(), hashes, tags)
+}
+
+func (c *client) AddTagsContext(ctx context.Context, hashes []string, tags []string) error {
 	var formData = url.Values{}
 	formData.Add("hashes", strings.Join(hashes, "|"))
 	formData.Add("tags", strings.Join(tags, ","))
-	var apiUrl = fmt.Sprintf("%s/api/v2/torrents/addTags", c.config.Address)
-	result, err := c.doRequest(&requestData{
+	var apiUrl = c.apiURL("torrents/addTags", nil)
+	result, err := c.doRequest(ctx, &requestData{
 		url:    apiUrl,
 		method: http.MethodPost,
 		body:   strings.NewReader(formData.Encode()),
@@ -1132,17 +1421,48 @@ func (c *client) AddTags(hashes []string, tags []string) error {
 	}
 
 	if result.code != 200 {
-		return errors.New("add torrent tags failed: " + string(result.body))
+		return newAPIError("add torrent tags failed", result)
 	}
 	return err
 }
 
+func (c *client) SetTags(hashes []string, tags []string) error {
+	return c.SetTagsContext(context.Background(), hashes, tags)
+}
+
+func (c *client) SetTagsContext(ctx context.Context, hashes []string, tags []string) error {
+	if err := c.requireVersion(ctx, "set torrent tags", versionSetTags); err != nil {
+		return err
+	}
+	var formData = url.Values{}
+	formData.Add("hashes", strings.Join(hashes, "|"))
+	formData.Add("tags", strings.Join(tags, ","))
+	var apiUrl = c.apiURL("torrents/setTags", nil)
+	result, err := c.doRequest(ctx, &requestData{
+		url:    apiUrl,
+		method: http.MethodPost,
+		body:   strings.NewReader(formData.Encode()),
+	})
+	if err != nil {
+		return err
+	}
+
+	if result.code != 200 {
+		return newAPIError("set torrent tags failed", result)
+	}
+	return nil
+}
+
 func (c *client) RemoveTags(hashes []string, tags []string) error {
+	return c.RemoveTagsContext(context.Background(), hashes, tags)
+}
+
+func (c *client) RemoveTagsContext(ctx context.Context, hashes []string, tags []string) error {
 	var formData = url.Values{}
 	formData.Add("hashes", strings.Join(hashes, "|"))
 	formData.Add("tags", strings.Join(tags, ","))
-	var apiUrl = fmt.Sprintf("%s/api/v2/torrents/removeTags", c.config.Address)
-	result, err := c.doRequest(&requestData{
+	var apiUrl = c.apiURL("torrents/removeTags", nil)
+	result, err := c.doRequest(ctx, &requestData{
 		url:    apiUrl,
 		method: http.MethodPost,
 		body:   strings.NewReader(formData.Encode()),
@@ -1152,14 +1472,18 @@ func (c *client) RemoveTags(hashes []string, tags []string) error {
 	}
 
 	if result.code != 200 {
-		return errors.New("remove torrent tags failed: " + string(result.body))
+		return newAPIError("remove torrent tags failed", result)
 	}
 	return err
 }
 
 func (c *client) GetTags() ([]string, error) {
-	var apiUrl = fmt.Sprintf("%s/api/v2/torrents/tags", c.config.Address)
-	result, err := c.doRequest(&requestData{
+	return c.GetTagsContext(context.Background())
+}
+
+func (c *client) GetTagsContext(ctx context.Context) ([]string, error) {
+	var apiUrl = c.apiURL("torrents/tags", nil)
+	result, err := c.doRequest(ctx, &requestData{
 		url:    apiUrl,
 		method: http.MethodGet,
 	})
@@ -1168,7 +1492,7 @@ func (c *client) GetTags() ([]string, error) {
 	}
 
 	if result.code != 200 {
-		return nil, errors.New("get tags failed: " + string(result.body))
+		return nil, newAPIError("get tags failed", result)
 	}
 	var data []string
 	err = sonic.Unmarshal(result.body, &data)
@@ -1176,10 +1500,14 @@ func (c *client) GetTags() ([]string, error) {
 }
 
 func (c *client) CreateTags(tags []string) error {
+	return c.CreateTagsContext(context.Background(), tags)
+}
+
+func (c *client) CreateTagsContext(ctx context.Context, tags []string) error {
 	var formData = url.Values{}
 	formData.Add("tags", strings.Join(tags, ","))
-	var apiUrl = fmt.Sprintf("%s/api/v2/torrents/createTags", c.config.Address)
-	result, err := c.doRequest(&requestData{
+	var apiUrl = c.apiURL("torrents/createTags", nil)
+	result, err := c.doRequest(ctx, &requestData{
 		url:    apiUrl,
 		method: http.MethodPost,
 		body:   strings.NewReader(formData.Encode()),
@@ -1189,16 +1517,20 @@ func (c *client) CreateTags(tags []string) error {
 	}
 
 	if result.code != 200 {
-		return errors.New("create tags failed: " + string(result.body))
+		return newAPIError("create tags failed", result)
 	}
 	return err
 }
 
 func (c *client) DeleteTags(tags []string) error {
+	return c.DeleteTagsContext(context.Background(), tags)
+}
+
+func (c *client) DeleteTagsContext(ctx context.Context, tags []string) error {
 	var formData = url.Values{}
 	formData.Add("tags", strings.Join(tags, ","))
-	var apiUrl = fmt.Sprintf("%s/api/v2/torrents/deleteTags", c.config.Address)
-	result, err := c.doRequest(&requestData{
+	var apiUrl = c.apiURL("torrents/deleteTags", nil)
+	result, err := c.doRequest(ctx, &requestData{
 		url:    apiUrl,
 		method: http.MethodPost,
 		body:   strings.NewReader(formData.Encode()),
@@ -1208,17 +1540,21 @@ func (c *client) DeleteTags(tags []string) error {
 	}
 
 	if result.code != 200 {
-		return errors.New("delete tags failed: " + string(result.body))
+		return newAPIError("delete tags failed", result)
 	}
 	return err
 }
 
 func (c *client) SetAutomaticManagement(hashes []string, enable bool) error {
+	return c.SetAutomaticManagementContext(context.Background(), hashes, enable)
+}
+
+func (c *client) SetAutomaticManagementContext(ctx context.Context, hashes []string, enable bool) error {
 	var formData = url.Values{}
 	formData.Add("hashes", strings.Join(hashes, "|"))
 	formData.Add("enable", strconv.FormatBool(enable))
-	var apiUrl = fmt.Sprintf("%s/api/v2/torrents/setAutoManagement", c.config.Address)
-	result, err := c.doRequest(&requestData{
+	var apiUrl = c.apiURL("torrents/setAutoManagement", nil)
+	result, err := c.doRequest(ctx, &requestData{
 		url:    apiUrl,
 		method: http.MethodPost,
 		body:   strings.NewReader(formData.Encode()),
@@ -1228,16 +1564,20 @@ func (c *client) SetAutomaticManagement(hashes []string, enable bool) error {
 	}
 
 	if result.code != 200 {
-		return errors.New("set automatic management failed: " + string(result.body))
+		return newAPIError("set automatic management failed", result)
 	}
 	return err
 }
 
 func (c *client) ToggleSequentialDownload(hashes []string) error {
+	return c.ToggleSequentialDownloadContext(context.Background(), hashes)
+}
+
+func (c *client) ToggleSequentialDownloadContext(ctx context.Context, hashes []string) error {
 	var formData = url.Values{}
 	formData.Add("hashes", strings.Join(hashes, "|"))
-	var apiUrl = fmt.Sprintf("%s/api/v2/torrents/toggleSequentialDownload", c.config.Address)
-	result, err := c.doRequest(&requestData{
+	var apiUrl = c.apiURL("torrents/toggleSequentialDownload", nil)
+	result, err := c.doRequest(ctx, &requestData{
 		url:    apiUrl,
 		method: http.MethodPost,
 		body:   strings.NewReader(formData.Encode()),
@@ -1247,16 +1587,20 @@ func (c *client) ToggleSequentialDownload(hashes []string) error {
 	}
 
 	if result.code != 200 {
-		return errors.New("toggle sequential download failed: " + string(result.body))
+		return newAPIError("toggle sequential download failed", result)
 	}
 	return err
 }
 
 func (c *client) SetFirstLastPiecePriority(hashes []string) error {
+	return c.SetFirstLastPiecePriorityContext(context.Background(), hashes)
+}
+
+func (c *client) SetFirstLastPiecePriorityContext(ctx context.Context, hashes []string) error {
 	var formData = url.Values{}
 	formData.Add("hashes", strings.Join(hashes, "|"))
-	var apiUrl = fmt.Sprintf("%s/api/v2/torrents/toggleFirstLastPiecePrio", c.config.Address)
-	result, err := c.doRequest(&requestData{
+	var apiUrl = c.apiURL("torrents/toggleFirstLastPiecePrio", nil)
+	result, err := c.doRequest(ctx, &requestData{
 		url:    apiUrl,
 		method: http.MethodPost,
 		body:   strings.NewReader(formData.Encode()),
@@ -1266,17 +1610,21 @@ func (c *client) SetFirstLastPiecePriority(hashes []string) error {
 	}
 
 	if result.code != 200 {
-		return errors.New("toggle first last piece prio failed: " + string(result.body))
+		return newAPIError("toggle first last piece prio failed", result)
 	}
 	return err
 }
 
 func (c *client) SetForceStart(hashes []string, force bool) error {
+	return c.SetForceStartContext(context.Background(), hashes, force)
+}
+
+func (c *client) SetForceStartContext(ctx context.Context, hashes []string, force bool) error {
 	var formData = url.Values{}
 	formData.Add("hashes", strings.Join(hashes, "|"))
 	formData.Add("value", strconv.FormatBool(force))
-	var apiUrl = fmt.Sprintf("%s/api/v2/torrents/setForceStart", c.config.Address)
-	result, err := c.doRequest(&requestData{
+	var apiUrl = c.apiURL("torrents/setForceStart", nil)
+	result, err := c.doRequest(ctx, &requestData{
 		url:    apiUrl,
 		method: http.MethodPost,
 		body:   strings.NewReader(formData.Encode()),
@@ -1286,17 +1634,21 @@ func (c *client) SetForceStart(hashes []string, force bool) error {
 	}
 
 	if result.code != 200 {
-		return errors.New("set force start failed: " + string(result.body))
+		return newAPIError("set force start failed", result)
 	}
 	return err
 }
 
 func (c *client) SetSuperSeeding(hashes []string, enable bool) error {
+	return c.SetSuperSeedingContext(context.Background(), hashes, enable)
+}
+
+func (c *client) SetSuperSeedingContext(ctx context.Context, hashes []string, enable bool) error {
 	var formData = url.Values{}
 	formData.Add("hashes", strings.Join(hashes, "|"))
 	formData.Add("value", strconv.FormatBool(enable))
-	var apiUrl = fmt.Sprintf("%s/api/v2/torrents/setSuperSeeding", c.config.Address)
-	result, err := c.doRequest(&requestData{
+	var apiUrl = c.apiURL("torrents/setSuperSeeding", nil)
+	result, err := c.doRequest(ctx, &requestData{
 		url:    apiUrl,
 		method: http.MethodPost,
 		body:   strings.NewReader(formData.Encode()),
@@ -1306,18 +1658,22 @@ func (c *client) SetSuperSeeding(hashes []string, enable bool) error {
 	}
 
 	if result.code != 200 {
-		return errors.New("set super seeding failed: " + string(result.body))
+		return newAPIError("set super seeding failed", result)
 	}
 	return err
 }
 
 func (c *client) RenameFile(hash, oldPath, newPath string) error {
+	return c.RenameFileContext(context.Background(), hash, oldPath, newPath)
+}
+
+func (c *client) RenameFileContext(ctx context.Context, hash, oldPath, newPath string) error {
 	var formData = url.Values{}
 	formData.Add("oldPath", oldPath)
 	formData.Add("newPath", newPath)
 	formData.Add("hash", hash)
-	var apiUrl = fmt.Sprintf("%s/api/v2/torrents/renameFile", c.config.Address)
-	result, err := c.doRequest(&requestData{
+	var apiUrl = c.apiURL("torrents/renameFile", nil)
+	result, err := c.doRequest(ctx, &requestData{
 		url:    apiUrl,
 		method: http.MethodPost,
 		body:   strings.NewReader(formData.Encode()),
@@ -1327,18 +1683,22 @@ func (c *client) RenameFile(hash, oldPath, newPath string) error {
 	}
 
 	if result.code != 200 {
-		return errors.New("rename file failed: " + string(result.body))
+		return newAPIError("rename file failed", result)
 	}
 	return nil
 }
 
 func (c *client) RenameFolder(hash, oldPath, newPath string) error {
+	return c.RenameFolderContext(context.Background(), hash, oldPath, newPath)
+}
+
+func (c *client) RenameFolderContext(ctx context.Context, hash, oldPath, newPath string) error {
 	var formData = url.Values{}
 	formData.Add("oldPath", oldPath)
 	formData.Add("newPath", newPath)
 	formData.Add("hash", hash)
-	var apiUrl = fmt.Sprintf("%s/api/v2/torrents/renameFolder", c.config.Address)
-	result, err := c.doRequest(&requestData{
+	var apiUrl = c.apiURL("torrents/renameFolder", nil)
+	result, err := c.doRequest(ctx, &requestData{
 		url:    apiUrl,
 		method: http.MethodPost,
 		body:   strings.NewReader(formData.Encode()),
@@ -1348,7 +1708,7 @@ func (c *client) RenameFolder(hash, oldPath, newPath string) error {
 	}
 
 	if result.code != 200 {
-		return errors.New("rename folder failed: " + string(result.body))
+		return newAPIError("rename folder failed", result)
 	}
 	return nil
 }
@@ -0,0 +1,274 @@
+package qbittorrent
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// torrentAddBatchSize max number of torrent files uploaded by one request of AddTorrents
+const torrentAddBatchSize = 50
+
+// TorrentAddResult result of a url or torrent file added by AddTorrents
+type TorrentAddResult struct {
+	// Source the url, the path of a file from TorrentFileFromPath or TorrentFilesFromFS, or the Filename
+	Source string
+	// Hash hash of the torrent file or magnet link, empty for http urls and unreadable files
+	Hash string
+	// Err nil if the torrent was submitted
+	Err error
+}
+
+// TorrentFileFromPath returns a torrent file that is read from the local path when it is uploaded
+func TorrentFileFromPath(name string) *TorrentAddFileMetadata {
+	return &TorrentAddFileMetadata{
+		Filename: filepath.Base(name),
+		Open: func() (io.ReadCloser, error) {
+			return os.Open(name)
+		},
+		source: name,
+	}
+}
+
+// TorrentFilesFromFS returns the torrent files of fsys matching the fs.Glob pattern, such as
+// TorrentFilesFromFS(os.DirFS("/watch"), "*.torrent"), the files are read when they are uploaded
+func TorrentFilesFromFS(fsys fs.FS, pattern string) ([]*TorrentAddFileMetadata, error) {
+	matches, err := fs.Glob(fsys, pattern)
+	if err != nil {
+		return nil, err
+	}
+	var files = make([]*TorrentAddFileMetadata, 0, len(matches))
+	for _, name := range matches {
+		name := name
+		files = append(files, &TorrentAddFileMetadata{
+			Filename: path.Base(name),
+			Open: func() (io.ReadCloser, error) {
+				return fsys.Open(name)
+			},
+			source: name,
+		})
+	}
+	return files, nil
+}
+
+// TorrentFileFromReader read the torrent file from r, a reader can only be read once, so the
+// content is kept in Data to be uploaded again on retries
+func TorrentFileFromReader(filename string, r io.Reader) (*TorrentAddFileMetadata, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("read torrent %s: %w", filename, err)
+	}
+	return &TorrentAddFileMetadata{Filename: filename, Data: data}, nil
+}
+
+// read returns the content of the torrent file
+func (m *TorrentAddFileMetadata) read() ([]byte, error) {
+	if m.Data != nil || m.Open == nil {
+		return m.Data, nil
+	}
+	file, err := m.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return io.ReadAll(file)
+}
+
+func (m *TorrentAddFileMetadata) sourceName() string {
+	if m.source != "" {
+		return m.source
+	}
+	return m.Filename
+}
+
+// torrentUpload is the multipart body of torrents/add, the torrent files are read again while the
+// body is streamed so that only one file is held in memory
+type torrentUpload struct {
+	boundary string
+	fields   url.Values
+	urls     []string
+	files    []*TorrentAddFileMetadata
+	sizes    []int64
+}
+
+func newTorrentUpload(fields url.Values) *torrentUpload {
+	return &torrentUpload{boundary: multipart.NewWriter(io.Discard).Boundary(), fields: fields}
+}
+
+// addFile read and parse the torrent file before it is added to the upload, returns its hash
+func (u *torrentUpload) addFile(file *TorrentAddFileMetadata) (string, error) {
+	data, err := file.read()
+	if err != nil {
+		return "", fmt.Errorf("read torrent %s: %w", file.Filename, err)
+	}
+	metainfo, err := ParseMetainfo(data)
+	if err != nil {
+		return "", fmt.Errorf("parse torrent %s: %w", file.Filename, err)
+	}
+	u.files = append(u.files, file)
+	u.sizes = append(u.sizes, int64(len(data)))
+	return metainfo.Hash(), nil
+}
+
+// write the multipart body, content returns the content of the i-th file
+func (u *torrentUpload) write(w io.Writer, content func(i int) (io.Reader, error)) error {
+	var writer = multipart.NewWriter(w)
+	if err := writer.SetBoundary(u.boundary); err != nil {
+		return err
+	}
+	for _, key := range sortedKeys(u.fields) {
+		if err := writer.WriteField(key, u.fields.Get(key)); err != nil {
+			return err
+		}
+	}
+	if len(u.urls) != 0 {
+		if err := writer.WriteField("urls", strings.Join(u.urls, "\n")); err != nil {
+			return err
+		}
+	}
+	for i, file := range u.files {
+		reader, err := content(i)
+		if err != nil {
+			return err
+		}
+		part, err := writer.CreateFormFile("torrents", file.Filename)
+		if err != nil {
+			return err
+		}
+		if _, err := io.Copy(part, reader); err != nil {
+			return err
+		}
+	}
+	return writer.Close()
+}
+
+// contentLength returns the length of the body, the server does not accept chunked requests
+func (u *torrentUpload) contentLength() (int64, error) {
+	var counter countWriter
+	err := u.write(&counter, func(int) (io.Reader, error) {
+		return strings.NewReader(""), nil
+	})
+	for _, size := range u.sizes {
+		counter += countWriter(size)
+	}
+	return int64(counter), err
+}
+
+// body stream the multipart body through a pipe, it is called again for every attempt
+func (u *torrentUpload) body() io.Reader {
+	reader, writer := io.Pipe()
+	go func() {
+		writer.CloseWithError(u.write(writer, func(i int) (io.Reader, error) {
+			data, err := u.files[i].read()
+			if err != nil {
+				return nil, fmt.Errorf("read torrent %s: %w", u.files[i].Filename, err)
+			}
+			if int64(len(data)) != u.sizes[i] {
+				return nil, fmt.Errorf("torrent %s changed during upload", u.files[i].Filename)
+			}
+			return bytes.NewReader(data), nil
+		}))
+	}()
+	return reader
+}
+
+type countWriter int64
+
+func (w *countWriter) Write(p []byte) (int, error) {
+	*w += countWriter(len(p))
+	return len(p), nil
+}
+
+// uploadTorrents send the upload by a single streamed request
+func (c *client) uploadTorrents(ctx context.Context, upload *torrentUpload) error {
+	length, err := upload.contentLength()
+	if err != nil {
+		return err
+	}
+	var apiUrl = c.apiURL("torrents/add", nil)
+	result, err := c.doRequest(ctx, &requestData{
+		url:           apiUrl,
+		method:        http.MethodPost,
+		contentType:   "multipart/form-data; boundary=" + upload.boundary,
+		newBody:       upload.body,
+		contentLength: length,
+	})
+	if err != nil {
+		return err
+	}
+	// the server answers 200 with Fails. when no torrent was added
+	if result.code != 200 || string(result.body) == "Fails." {
+		return newAPIError("add torrents failed", result)
+	}
+	return nil
+}
+
+func (c *client) AddTorrents(opt *TorrentAddOption) ([]*TorrentAddResult, error) {
+	return c.AddTorrentsContext(context.Background(), opt)
+}
+
+func (c *client) AddTorrentsContext(ctx context.Context, opt *TorrentAddOption) ([]*TorrentAddResult, error) {
+	if len(opt.URLs) == 0 && len(opt.Torrents) == 0 {
+		return nil, errors.New("no torrent url or data provided")
+	}
+	version, err := c.webAPIVersion(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var fields = opt.formFields(version)
+
+	var results = make([]*TorrentAddResult, 0, len(opt.URLs)+len(opt.Torrents))
+	var upload = newTorrentUpload(fields)
+	var pending []*TorrentAddResult
+	var flush = func() {
+		if err := c.uploadTorrents(ctx, upload); err != nil {
+			for _, result := range pending {
+				result.Err = err
+			}
+		}
+		upload, pending = newTorrentUpload(fields), nil
+	}
+
+	// the urls are sent with the first batch of files
+	for _, u := range opt.URLs {
+		var result = &TorrentAddResult{Source: u}
+		if magnet, err := ParseMagnet(u); err == nil {
+			result.Hash = magnet.Hash()
+		}
+		upload.urls = append(upload.urls, u)
+		results, pending = append(results, result), append(pending, result)
+	}
+	for _, file := range opt.Torrents {
+		var result = &TorrentAddResult{Source: file.sourceName()}
+		results = append(results, result)
+		if result.Hash, result.Err = upload.addFile(file); result.Err != nil {
+			continue
+		}
+		pending = append(pending, result)
+		if len(upload.files) == torrentAddBatchSize {
+			flush()
+		}
+	}
+	if len(pending) != 0 {
+		flush()
+	}
+
+	var errs []error
+	for _, result := range results {
+		if result.Err == nil {
+			return results, nil
+		}
+		errs = append(errs, fmt.Errorf("%s: %w", result.Source, result.Err))
+	}
+	return results, errors.Join(errs...)
+}
@@ -0,0 +1,203 @@
+package qbittorrent
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"testing/fstest"
+
+	"github.com/xuthus5/qbittorrent-client-go/qbittorrent/qbittest"
+)
+
+// torrentFixture returns a single-file torrent named name and its hash
+func torrentFixture(name string) ([]byte, string) {
+	info := map[string]any{"length": 1024, "name": name, "piece length": 16384, "pieces": strings.Repeat("a", 20)}
+	sum := sha1.Sum(bencodeEncode(info))
+	return bencodeEncode(map[string]any{"info": info}), hex.EncodeToString(sum[:])
+}
+
+// recordUploads records the content length and transfer encoding of the torrents/add requests
+func recordUploads(mu *sync.Mutex, uploads *[]*http.Request) Middleware {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			if req.URL.Path == "/api/v2/torrents/add" {
+				mu.Lock()
+				*uploads = append(*uploads, req)
+				mu.Unlock()
+			}
+			return next.RoundTrip(req)
+		})
+	}
+}
+
+func TestClient_AddTorrents(t *testing.T) {
+	server := qbittest.NewServer()
+	defer server.Close()
+	var mu sync.Mutex
+	var uploads []*http.Request
+	client, err := NewClient(&Config{
+		Address:     server.URL,
+		Username:    qbittest.DefaultUsername,
+		Password:    qbittest.DefaultPassword,
+		Middlewares: []Middleware{recordUploads(&mu, &uploads)},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var fsys = fstest.MapFS{"broken.torrent": {Data: []byte("not a torrent")}, "notes.txt": {Data: []byte("notes")}}
+	var expected []string
+	for i := 0; i < torrentAddBatchSize+10; i++ {
+		data, hash := torrentFixture(fmt.Sprintf("file%03d.iso", i))
+		fsys[fmt.Sprintf("file%03d.torrent", i)] = &fstest.MapFile{Data: data}
+		expected = append(expected, hash)
+	}
+	files, err := TorrentFilesFromFS(fsys, "*.torrent")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	local, localHash := torrentFixture("local.iso")
+	path := filepath.Join(t.TempDir(), "local.torrent")
+	if err := os.WriteFile(path, local, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	readerData, readerHash := torrentFixture("reader.iso")
+	reader, err := TorrentFileFromReader("reader.torrent", bytes.NewReader(readerData))
+	if err != nil {
+		t.Fatal(err)
+	}
+	const magnetHash = "f23daefbe8d24d3dd882b44cb0b4f762bc23b4fc"
+
+	// the session is expired, the first batch is streamed again after login
+	server.ExpireSessions()
+	results, err := client.Torrent().AddTorrents(&TorrentAddOption{
+		URLs:     []string{"magnet:?xt=urn:btih:" + magnetHash},
+		Torrents: append(files, TorrentFileFromPath(path), reader, TorrentFileFromPath(filepath.Join(t.TempDir(), "missing.torrent"))),
+		Category: "watch",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != len(expected)+5 {
+		t.Fatalf("unexpected results: %d", len(results))
+	}
+	if results[0].Source != "magnet:?xt=urn:btih:"+magnetHash || results[0].Hash != magnetHash || results[0].Err != nil {
+		t.Fatalf("unexpected magnet result: %+v", results[0])
+	}
+	if results[1].Source != "broken.torrent" || results[1].Err == nil || results[1].Hash != "" {
+		t.Fatalf("expected the broken file to fail: %+v", results[1])
+	}
+	for i, hash := range expected {
+		if result := results[i+2]; result.Hash != hash || result.Err != nil {
+			t.Fatalf("unexpected result: %+v", result)
+		}
+	}
+	tail := results[len(results)-3:]
+	if tail[0].Source != path || tail[0].Hash != localHash || tail[1].Source != "reader.torrent" || tail[1].Hash != readerHash {
+		t.Fatalf("unexpected results: %+v %+v", tail[0], tail[1])
+	}
+	if tail[2].Err == nil || !strings.HasSuffix(tail[2].Source, "missing.torrent") {
+		t.Fatalf("expected the missing file to fail: %+v", tail[2])
+	}
+
+	for _, hash := range append(expected, magnetHash, localHash, readerHash) {
+		if torrent, ok := server.Torrent(hash); !ok || torrent.Category != "watch" {
+			t.Fatalf("expected torrent %s to be added", hash)
+		}
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	// the first batch twice because of the re-login, then the second batch
+	if len(uploads) != 3 {
+		t.Fatalf("unexpected number of uploads: %d", len(uploads))
+	}
+	for _, req := range uploads {
+		if req.ContentLength <= 0 || len(req.TransferEncoding) != 0 {
+			t.Fatalf("expected a streamed body with a content length, got %d %v", req.ContentLength, req.TransferEncoding)
+		}
+	}
+}
+
+func TestClient_AddNewTorrentMixed(t *testing.T) {
+	client, server := newTestClient(t)
+	data, hash := torrentFixture("mixed.iso")
+	const magnetHash = "f23daefbe8d24d3dd882b44cb0b4f762bc23b4fc"
+	hashes, err := client.Torrent().AddNewTorrent(&TorrentAddOption{
+		URLs:     []string{"magnet:?xt=urn:btih:" + magnetHash},
+		Torrents: []*TorrentAddFileMetadata{{Filename: "mixed.torrent", Data: data}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(hashes) != 2 || hashes[0] != magnetHash || hashes[1] != hash {
+		t.Fatalf("unexpected hashes: %v", hashes)
+	}
+	for _, hash := range hashes {
+		if _, ok := server.Torrent(hash); !ok {
+			t.Fatalf("expected torrent %s to be added", hash)
+		}
+	}
+
+	if _, err := client.Torrent().AddNewTorrent(&TorrentAddOption{
+		Torrents: []*TorrentAddFileMetadata{TorrentFileFromPath(filepath.Join(t.TempDir(), "missing.torrent"))},
+	}); err == nil {
+		t.Fatal("expected an error for a missing torrent file")
+	}
+}
+
+func TestClient_AddTorrentsFailed(t *testing.T) {
+	client, server := newTestClient(t)
+	results, err := client.Torrent().AddTorrents(&TorrentAddOption{
+		Torrents: []*TorrentAddFileMetadata{{Filename: "broken.torrent", Data: []byte("not a torrent")}},
+	})
+	if err == nil || len(results) != 1 || results[0].Err == nil || !strings.Contains(err.Error(), "broken.torrent") {
+		t.Fatalf("expected an error for the invalid file: %v %v", results, err)
+	}
+
+	// every batch is rejected by the server
+	data, _ := torrentFixture("rejected.iso")
+	client, err = NewClient(&Config{
+		Address:            server.URL,
+		Username:           qbittest.DefaultUsername,
+		Password:           qbittest.DefaultPassword,
+		DisableAutoReLogin: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	server.ExpireSessions()
+	results, err = client.Torrent().AddTorrents(&TorrentAddOption{
+		URLs:     []string{"magnet:?xt=urn:btih:f23daefbe8d24d3dd882b44cb0b4f762bc23b4fc"},
+		Torrents: []*TorrentAddFileMetadata{{Filename: "rejected.torrent", Data: data}},
+	})
+	if err == nil || len(results) != 2 || results[0].Err == nil || results[1].Err == nil {
+		t.Fatalf("expected the rejected upload to fail: %v %v", results, err)
+	}
+}
+
+func TestClient_AddNewTorrentDuplicate(t *testing.T) {
+	client, _ := newTestClient(t)
+	data, _ := torrentFixture("duplicate.iso")
+	opt := &TorrentAddOption{Torrents: []*TorrentAddFileMetadata{{Filename: "duplicate.torrent", Data: data}}}
+	if _, err := client.Torrent().AddNewTorrent(opt); err != nil {
+		t.Fatal(err)
+	}
+	// nothing is added and the server answers 200 with Fails.
+	var apiErr *APIError
+	if _, err := client.Torrent().AddNewTorrent(opt); !errors.As(err, &apiErr) || apiErr.StatusCode != 200 {
+		t.Fatalf("expected an APIError for the duplicate, got %v", err)
+	}
+	results, err := client.Torrent().AddTorrents(opt)
+	if err == nil || len(results) != 1 || results[0].Err == nil {
+		t.Fatalf("expected the duplicate to fail: %v %v", results, err)
+	}
+}
@@ -0,0 +1,128 @@
+package qbittorrent
+
+// TorrentState is the state of a torrent reported by TorrentInfo.State
+type TorrentState string
+
+const (
+	StateError              TorrentState = "error"              // some error occurred, applies to paused torrents
+	StateMissingFiles       TorrentState = "missingFiles"       // torrent data files is missing
+	StateUploading          TorrentState = "uploading"          // torrent is being seeded and data is being transferred
+	StatePausedUP           TorrentState = "pausedUP"           // torrent is paused and has finished downloading, before qBittorrent 5.0
+	StateStoppedUP          TorrentState = "stoppedUP"          // torrent is stopped and has finished downloading, qBittorrent 5.0+
+	StateQueuedUP           TorrentState = "queuedUP"           // queuing is enabled and torrent is queued for upload
+	StateStalledUP          TorrentState = "stalledUP"          // torrent is being seeded, but no connection were made
+	StateCheckingUP         TorrentState = "checkingUP"         // torrent has finished downloading and is being checked
+	StateForcedUP           TorrentState = "forcedUP"           // torrent is forced to uploading and ignore queue limit
+	StateAllocating         TorrentState = "allocating"         // torrent is allocating disk space for download
+	StateDownloading        TorrentState = "downloading"        // torrent is being downloaded and data is being transferred
+	StateMetaDL             TorrentState = "metaDL"             // torrent has just started downloading and is fetching metadata
+	StateForcedMetaDL       TorrentState = "forcedMetaDL"       // torrent is forced to fetching metadata and ignore queue limit
+	StatePausedDL           TorrentState = "pausedDL"           // torrent is paused and has not finished downloading, before qBittorrent 5.0
+	StateStoppedDL          TorrentState = "stoppedDL"          // torrent is stopped and has not finished downloading, qBittorrent 5.0+
+	StateQueuedDL           TorrentState = "queuedDL"           // queuing is enabled and torrent is queued for download
+	StateStalledDL          TorrentState = "stalledDL"          // torrent is being downloaded, but no connection were made
+	StateCheckingDL         TorrentState = "checkingDL"         // same as checkingUP, but torrent has not finished downloading
+	StateForcedDL           TorrentState = "forcedDL"           // torrent is forced to downloading to ignore queue limit
+	StateCheckingResumeData TorrentState = "checkingResumeData" // checking resume data on qBittorrent startup
+	StateMoving             TorrentState = "moving"             // torrent is moving to another location
+	StateUnknown            TorrentState = "unknown"            // unknown status
+)
+
+// IsDownloading reports whether the torrent is downloading, including stalled, queued, forced and
+// checking torrents that have not finished downloading
+func (s TorrentState) IsDownloading() bool {
+	switch s {
+	case StateDownloading, StateMetaDL, StateForcedMetaDL, StateStalledDL, StateQueuedDL,
+		StateCheckingDL, StateForcedDL, StateAllocating:
+		return true
+	}
+	return false
+}
+
+// IsSeeding reports whether the torrent is seeding, including stalled, queued, forced and checking
+// torrents that have finished downloading
+func (s TorrentState) IsSeeding() bool {
+	switch s {
+	case StateUploading, StateStalledUP, StateQueuedUP, StateCheckingUP, StateForcedUP:
+		return true
+	}
+	return false
+}
+
+// IsPaused reports whether the torrent is paused, stoppedUP and stoppedDL of qBittorrent 5.0 are
+// treated as paused
+func (s TorrentState) IsPaused() bool {
+	switch s {
+	case StatePausedUP, StatePausedDL, StateStoppedUP, StateStoppedDL:
+		return true
+	}
+	return false
+}
+
+// IsErrored reports whether the torrent has an error or missing files
+func (s TorrentState) IsErrored() bool {
+	return s == StateError || s == StateMissingFiles
+}
+
+// IsCompleted reports whether the torrent has finished downloading
+func (s TorrentState) IsCompleted() bool {
+	switch s {
+	case StateUploading, StatePausedUP, StateStoppedUP, StateQueuedUP, StateStalledUP, StateCheckingUP, StateForcedUP:
+		return true
+	}
+	return false
+}
+
+// IsChecking reports whether the torrent data or resume data is being checked
+func (s TorrentState) IsChecking() bool {
+	return s == StateCheckingUP || s == StateCheckingDL || s == StateCheckingResumeData
+}
+
+// IsStalled reports whether the torrent is active but no connection were made
+func (s TorrentState) IsStalled() bool {
+	return s == StateStalledUP || s == StateStalledDL
+}
+
+// IsQueued reports whether the torrent is waiting in the queue
+func (s TorrentState) IsQueued() bool {
+	return s == StateQueuedUP || s == StateQueuedDL
+}
+
+// TorrentFilter filters the torrent list by state, see TorrentOption.Filter
+type TorrentFilter string
+
+const (
+	FilterAll                TorrentFilter = "all"
+	FilterDownloading        TorrentFilter = "downloading"
+	FilterSeeding            TorrentFilter = "seeding"
+	FilterCompleted          TorrentFilter = "completed"
+	FilterPaused             TorrentFilter = "paused"  // the same as FilterStopped, sent as stopped to qBittorrent 5.0+
+	FilterStopped            TorrentFilter = "stopped" // the same as FilterPaused, sent as paused before qBittorrent 5.0
+	FilterActive             TorrentFilter = "active"
+	FilterInactive           TorrentFilter = "inactive"
+	FilterResumed            TorrentFilter = "resumed" // the same as FilterRunning, sent as running to qBittorrent 5.0+
+	FilterRunning            TorrentFilter = "running" // the same as FilterResumed, sent as resumed before qBittorrent 5.0
+	FilterStalled            TorrentFilter = "stalled"
+	FilterStalledUploading   TorrentFilter = "stalled_uploading"
+	FilterStalledDownloading TorrentFilter = "stalled_downloading"
+	FilterChecking           TorrentFilter = "checking"
+	FilterMoving             TorrentFilter = "moving"
+	FilterErrored            TorrentFilter = "errored"
+)
+
+// forVersion returns the name of the filter known by the server version, qBittorrent 5.0 renamed
+// paused and resumed to stopped and running
+func (f TorrentFilter) forVersion(version Version) TorrentFilter {
+	var stopStart = version.AtLeast(versionStopStart)
+	switch {
+	case f == FilterPaused && stopStart:
+		return FilterStopped
+	case f == FilterStopped && !stopStart:
+		return FilterPaused
+	case f == FilterResumed && stopStart:
+		return FilterRunning
+	case f == FilterRunning && !stopStart:
+		return FilterResumed
+	}
+	return f
+}
@@ -0,0 +1,87 @@
+package qbittorrent
+
+import (
+	"testing"
+
+	"github.com/xuthus5/qbittorrent-client-go/qbittorrent/qbittest"
+)
+
+func TestTorrentState(t *testing.T) {
+	for _, state := range []TorrentState{StatePausedUP, StateStoppedUP, StatePausedDL, StateStoppedDL} {
+		if !state.IsPaused() || state.IsDownloading() || state.IsSeeding() {
+			t.Fatalf("%s should only be paused", state)
+		}
+	}
+	if !StateStalledDL.IsDownloading() || StateStalledDL.IsCompleted() || !StateStalledDL.IsStalled() {
+		t.Fatal("stalledDL should be a stalled download")
+	}
+	if !StateForcedUP.IsSeeding() || !StateForcedUP.IsCompleted() {
+		t.Fatal("forcedUP should be seeding")
+	}
+	if !StateMissingFiles.IsErrored() || StateUnknown.IsErrored() {
+		t.Fatal("only error and missingFiles should be errored")
+	}
+}
+
+func TestClient_GetTorrentsFilter(t *testing.T) {
+	client, server := newTestClient(t)
+	server.AddTorrent(&qbittest.Torrent{Hash: "8c212779b4abde7c6bc608063a0d008b7e40ce32", State: "stalledDL"})
+	server.AddTorrent(&qbittest.Torrent{Hash: "d1f0ec5b4ab3a0c5aa8e2b0f3c1d6b4cf0a6a3d2", State: "stalledUP", Progress: 1})
+
+	torrents, err := client.Torrent().GetTorrents(&TorrentOption{Filter: FilterStalledDownloading})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(torrents) != 1 || torrents[0].State != StateStalledDL || !torrents[0].State.IsDownloading() {
+		t.Fatalf("unexpected torrents: %+v", torrents)
+	}
+}
+
+func TestTorrentFilter_ForVersion(t *testing.T) {
+	for _, tt := range []struct {
+		filter   TorrentFilter
+		version  Version
+		expected TorrentFilter
+	}{
+		{FilterPaused, Version{2, 9, 3}, FilterPaused},
+		{FilterStopped, Version{2, 9, 3}, FilterPaused},
+		{FilterRunning, Version{2, 9, 3}, FilterResumed},
+		{FilterPaused, Version{2, 11, 0}, FilterStopped},
+		{FilterResumed, Version{2, 11, 0}, FilterRunning},
+		{FilterRunning, Version{2, 11, 0}, FilterRunning},
+		{FilterSeeding, Version{2, 11, 0}, FilterSeeding},
+	} {
+		if filter := tt.filter.forVersion(tt.version); filter != tt.expected {
+			t.Fatalf("%s %s: expected %s, got %s", tt.filter, tt.version, tt.expected, filter)
+		}
+	}
+}
+
+func TestClient_GetTorrentsFilterVersion(t *testing.T) {
+	const stopped, running = "8c212779b4abde7c6bc608063a0d008b7e40ce32", "d1f0ec5b4ab3a0c5aa8e2b0f3c1d6b4cf0a6a3d2"
+	for _, version := range []string{"2.9.3", "2.11.0"} {
+		client, server := newTestClient(t)
+		server.SetVersion("v5.0.0", version)
+		if err := client.Authentication().Login(); err != nil {
+			t.Fatal(err)
+		}
+		server.AddTorrent(&qbittest.Torrent{Hash: stopped, State: "stoppedDL"})
+		server.AddTorrent(&qbittest.Torrent{Hash: running, State: "downloading"})
+
+		// both names of the renamed filters are sent with the name of the server version
+		for filter, expected := range map[TorrentFilter]string{
+			FilterPaused:  stopped,
+			FilterStopped: stopped,
+			FilterResumed: running,
+			FilterRunning: running,
+		} {
+			torrents, err := client.Torrent().GetTorrents(&TorrentOption{Filter: filter})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(torrents) != 1 || torrents[0].Hash != expected {
+				t.Fatalf("%s %s: unexpected torrents: %+v", version, filter, torrents)
+			}
+		}
+	}
+}
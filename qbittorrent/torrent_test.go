@@ -1,7 +1,8 @@
 package qbittorrent
 
 import (
-	"os"
+	"net/url"
+	"reflect"
 	"testing"
 
 	"github.com/bytedance/sonic"
@@ -133,11 +134,8 @@ func TestClient_ReAnnounceTorrents(t *testing.T) {
 }
 
 func TestClient_AddNewTorrent(t *testing.T) {
-	fileContent, err := os.ReadFile("C:\\Users\\xuthu\\Downloads\\bbbbb.torrent")
-	if err != nil {
-		t.Fatal(err)
-	}
-	err = c.Torrent().AddNewTorrent(&TorrentAddOption{
+	fileContent := []byte("d4:infod6:lengthi1024e4:name9:bbbbb.iso12:piece lengthi16384e6:pieces20:aaaaaaaaaaaaaaaaaaaaee")
+	hashes, err := c.Torrent().AddNewTorrent(&TorrentAddOption{
 		Torrents: []*TorrentAddFileMetadata{
 			{
 				//Filename: "ttttt.torrent",
@@ -161,7 +159,107 @@ func TestClient_AddNewTorrent(t *testing.T) {
 	if err != nil {
 		t.Fatal(err)
 	}
-	t.Log("torrent added")
+	if len(hashes) != 1 || hashes[0] != "234e5bb6a4a74e715e588f3bf2d5f1050eb26231" {
+		t.Fatalf("unexpected hashes: %v", hashes)
+	}
+	if _, ok := server.Torrent(hashes[0]); !ok {
+		t.Fatalf("expected torrent %s to be added", hashes[0])
+	}
+
+	_, err = c.Torrent().AddNewTorrent(&TorrentAddOption{
+		Torrents: []*TorrentAddFileMetadata{{Filename: "broken.torrent", Data: []byte("not a torrent")}},
+	})
+	if err == nil {
+		t.Fatal("expected an error for an invalid torrent file")
+	}
+}
+
+func TestTorrentAddOption_FormFields(t *testing.T) {
+	yes, no := true, false
+	opt := &TorrentAddOption{
+		SavePath:                 "/downloads/movies",
+		DownloadPath:             "/incomplete",
+		UseDownloadPath:          &yes,
+		Cookies:                  "uid=1",
+		Tags:                     []string{"a", "b"},
+		Stopped:                  true,
+		Forced:                   true,
+		AddToTopOfQueue:          &no,
+		StopCondition:            StopConditionMetadataReceived,
+		ContentLayout:            ContentLayoutNoSubfolder,
+		InactiveSeedingTimeLimit: 30,
+	}
+	common := url.Values{
+		"savepath":                 {"/downloads/movies"},
+		"downloadPath":             {"/incomplete"},
+		"useDownloadPath":          {"true"},
+		"cookie":                   {"uid=1"},
+		"tags":                     {"a,b"},
+		"forced":                   {"true"},
+		"addToTopOfQueue":          {"false"},
+		"stopCondition":            {"MetadataReceived"},
+		"inactiveSeedingTimeLimit": {"30"},
+	}
+	with := func(extra url.Values) url.Values {
+		var form = url.Values{}
+		for key, values := range common {
+			form[key] = values
+		}
+		for key, values := range extra {
+			form[key] = values
+		}
+		return form
+	}
+
+	for version, expected := range map[Version]url.Values{
+		{2, 2, 0}:  with(url.Values{"paused": {"true"}, "root_folder": {"false"}}),
+		{2, 9, 3}:  with(url.Values{"paused": {"true"}, "contentLayout": {"NoSubfolder"}}),
+		{2, 11, 0}: with(url.Values{"stopped": {"true"}, "contentLayout": {"NoSubfolder"}}),
+	} {
+		if form := opt.formFields(version); !reflect.DeepEqual(form, expected) {
+			t.Fatalf("%s: unexpected form %v", version, form)
+		}
+	}
+
+	// the deprecated fields are sent with the names of the server version
+	form := (&TorrentAddOption{Paused: true, RootFolder: true}).formFields(Version{2, 11, 0})
+	if !reflect.DeepEqual(form, url.Values{"stopped": {"true"}, "contentLayout": {"Subfolder"}}) {
+		t.Fatalf("unexpected form %v", form)
+	}
+}
+
+func TestClient_AddNewTorrentOptions(t *testing.T) {
+	client, server := newTestClient(t)
+	const hash = "f23daefbe8d24d3dd882b44cb0b4f762bc23b4fc"
+	yes := true
+	opt := &TorrentAddOption{
+		URLs:                     []string{"magnet:?xt=urn:btih:" + hash},
+		SavePath:                 "/downloads/movies",
+		DownloadPath:             "/incomplete",
+		UseDownloadPath:          &yes,
+		Stopped:                  true,
+		InactiveSeedingTimeLimit: 30,
+	}
+	if _, err := client.Torrent().AddNewTorrent(opt); err != nil {
+		t.Fatal(err)
+	}
+	torrent, _ := server.Torrent(hash)
+	if torrent.State != "pausedDL" || torrent.SavePath != "/downloads/movies" || torrent.DownloadPath != "/incomplete" ||
+		torrent.InactiveSeedingTimeLimit != 30 {
+		t.Fatalf("unexpected torrent: %+v", torrent)
+	}
+
+	server.RemoveTorrent(hash)
+	server.SetVersion("v5.0.0", "2.11.0")
+	if err := client.Authentication().Login(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.Torrent().AddNewTorrent(opt); err != nil {
+		t.Fatal(err)
+	}
+	if torrent, _ := server.Torrent(hash); torrent.State != "stoppedDL" {
+		t.Fatalf("expected the torrent to be stopped, got %s", torrent.State)
+	}
 }
 
 func TestClient_AddTrackers(t *testing.T) {
@@ -1,10 +1,10 @@
 package qbittorrent
 
 import (
-	"errors"
-	"fmt"
+	"context"
 	"net/http"
 	"net/url"
+	"strconv"
 	"strings"
 
 	"github.com/bytedance/sonic"
@@ -27,28 +27,48 @@ type TransferStatusBar struct {
 type Transfer interface {
 	// GlobalStatusBar usually see in qBittorrent status bar
 	GlobalStatusBar() (*TransferStatusBar, error)
+	// GlobalStatusBarContext is the context-aware version of GlobalStatusBar
+	GlobalStatusBarContext(ctx context.Context) (*TransferStatusBar, error)
 	// BanPeers the peer to ban, or multiple peers separated by a pipe.
 	// each peer is host:port
 	BanPeers(peers []string) error
+	// BanPeersContext is the context-aware version of BanPeers
+	BanPeersContext(ctx context.Context, peers []string) error
 	// GetSpeedLimitsMode get alternative speed limits state
 	GetSpeedLimitsMode() (string, error)
+	// GetSpeedLimitsModeContext is the context-aware version of GetSpeedLimitsMode
+	GetSpeedLimitsModeContext(ctx context.Context) (string, error)
 	// ToggleSpeedLimitsMode toggle alternative speed limits
 	ToggleSpeedLimitsMode() error
+	// ToggleSpeedLimitsModeContext is the context-aware version of ToggleSpeedLimitsMode
+	ToggleSpeedLimitsModeContext(ctx context.Context) error
 	// GetGlobalUploadLimit get global upload limit, the response is the value of current global download speed
 	// limit in bytes/second; this value will be zero if no limit is applied.
 	GetGlobalUploadLimit() (string, error)
+	// GetGlobalUploadLimitContext is the context-aware version of GetGlobalUploadLimit
+	GetGlobalUploadLimitContext(ctx context.Context) (string, error)
 	// SetGlobalUploadLimit set global upload limit, set in bytes/second
 	SetGlobalUploadLimit(int) error
+	// SetGlobalUploadLimitContext is the context-aware version of SetGlobalUploadLimit
+	SetGlobalUploadLimitContext(ctx context.Context, limit int) error
 	// GetGlobalDownloadLimit get global download limit, the response is the value of current global download speed
 	// limit in bytes/second; this value will be zero if no limit is applied.
 	GetGlobalDownloadLimit() (string, error)
+	// GetGlobalDownloadLimitContext is the context-aware version of GetGlobalDownloadLimit
+	GetGlobalDownloadLimitContext(ctx context.Context) (string, error)
 	// SetGlobalDownloadLimit set global download limit, set in bytes/second
 	SetGlobalDownloadLimit(int) error
+	// SetGlobalDownloadLimitContext is the context-aware version of SetGlobalDownloadLimit
+	SetGlobalDownloadLimitContext(ctx context.Context, limit int) error
 }
 
 func (c *client) GlobalStatusBar() (*TransferStatusBar, error) {
-	apiUrl := fmt.Sprintf("%s/api/v2/transfer/info", c.config.Address)
-	result, err := c.doRequest(&requestData{
+	return c.GlobalStatusBarContext(context.Background())
+}
+
+func (c *client) GlobalStatusBarContext(ctx context.Context) (*TransferStatusBar, error) {
+	apiUrl := c.apiURL("transfer/info", nil)
+	result, err := c.doRequest(ctx, &requestData{
 		url: apiUrl,
 	})
 	if err != nil {
@@ -56,7 +76,7 @@ func (c *client) GlobalStatusBar() (*TransferStatusBar, error) {
 	}
 
 	if result.code != 200 {
-		return nil, errors.New("get global transfer status bar failed: " + string(result.body))
+		return nil, newAPIError("get global transfer status bar failed", result)
 	}
 
 	var data = new(TransferStatusBar)
@@ -68,10 +88,14 @@ func (c *client) GlobalStatusBar() (*TransferStatusBar, error) {
 }
 
 func (c *client) BanPeers(peers []string) error {
-	apiUrl := fmt.Sprintf("%s/api/v2/transfer/banPeers", c.config.Address)
+	return c.BanPeersContext(context.Background(), peers)
+}
+
+func (c *client) BanPeersContext(ctx context.Context, peers []string) error {
+	apiUrl := c.apiURL("transfer/banPeers", nil)
 	var form = url.Values{}
 	form.Add("peers", strings.Join(peers, "|"))
-	result, err := c.doRequest(&requestData{
+	result, err := c.doRequest(ctx, &requestData{
 		url:    apiUrl,
 		method: http.MethodPost,
 		body:   strings.NewReader(form.Encode()),
@@ -81,15 +105,19 @@ func (c *client) BanPeers(peers []string) error {
 	}
 
 	if result.code != 200 {
-		return errors.New("ban peers failed: " + string(result.body))
+		return newAPIError("ban peers failed", result)
 	}
 
 	return nil
 }
 
 func (c *client) GetSpeedLimitsMode() (string, error) {
-	apiUrl := fmt.Sprintf("%s/api/v2/transfer/speedLimitsMode", c.config.Address)
-	result, err := c.doRequest(&requestData{
+	return c.GetSpeedLimitsModeContext(context.Background())
+}
+
+func (c *client) GetSpeedLimitsModeContext(ctx context.Context) (string, error) {
+	apiUrl := c.apiURL("transfer/speedLimitsMode", nil)
+	result, err := c.doRequest(ctx, &requestData{
 		url: apiUrl,
 	})
 	if err != nil {
@@ -97,15 +125,19 @@ func (c *client) GetSpeedLimitsMode() (string, error) {
 	}
 
 	if result.code != 200 {
-		return "", errors.New("ban peers failed: " + string(result.body))
+		return "", newAPIError("ban peers failed", result)
 	}
 
 	return string(result.body), nil
 }
 
 func (c *client) ToggleSpeedLimitsMode() error {
-	apiUrl := fmt.Sprintf("%s/api/v2/transfer/toggleSpeedLimitsMode", c.config.Address)
-	result, err := c.doRequest(&requestData{
+	return c.ToggleSpeedLimitsModeContext(context.Background())
+}
+
+func (c *client) ToggleSpeedLimitsModeContext(ctx context.Context) error {
+	apiUrl := c.apiURL("transfer/toggleSpeedLimitsMode", nil)
+	result, err := c.doRequest(ctx, &requestData{
 		url:    apiUrl,
 		method: http.MethodPost,
 	})
@@ -114,15 +146,19 @@ func (c *client) ToggleSpeedLimitsMode() error {
 	}
 
 	if result.code != 200 {
-		return errors.New("ban peers failed: " + string(result.body))
+		return newAPIError("ban peers failed", result)
 	}
 
 	return nil
 }
 
 func (c *client) GetGlobalUploadLimit() (string, error) {
-	apiUrl := fmt.Sprintf("%s/api/v2/transfer/uploadLimit", c.config.Address)
-	result, err := c.doRequest(&requestData{
+	return c.GetGlobalUploadLimitContext(context.Background())
+}
+
+func (c *client) GetGlobalUploadLimitContext(ctx context.Context) (string, error) {
+	apiUrl := c.apiURL("transfer/uploadLimit", nil)
+	result, err := c.doRequest(ctx, &requestData{
 		url: apiUrl,
 	})
 	if err != nil {
@@ -130,15 +166,19 @@ func (c *client) GetGlobalUploadLimit() (string, error) {
 	}
 
 	if result.code != 200 {
-		return "", errors.New("get global upload limit failed: " + string(result.body))
+		return "", newAPIError("get global upload limit failed", result)
 	}
 
 	return string(result.body), nil
 }
 
 func (c *client) SetGlobalUploadLimit(limit int) error {
-	apiUrl := fmt.Sprintf("%s/api/v2/transfer/setUploadLimit?limit=%d", c.config.Address, limit)
-	result, err := c.doRequest(&requestData{
+	return c.SetGlobalUploadLimitContext(context.Background(), limit)
+}
+
+func (c *client) SetGlobalUploadLimitContext(ctx context.Context, limit int) error {
+	apiUrl := c.apiURL("transfer/setUploadLimit", url.Values{"limit": {strconv.Itoa(limit)}})
+	result, err := c.doRequest(ctx, &requestData{
 		url: apiUrl,
 	})
 	if err != nil {
@@ -146,15 +186,19 @@ func (c *client) SetGlobalUploadLimit(limit int) error {
 	}
 
 	if result.code != 200 {
-		return errors.New("set global upload limit failed: " + string(result.body))
+		return newAPIError("set global upload limit failed", result)
 	}
 
 	return nil
 }
 
 func (c *client) GetGlobalDownloadLimit() (string, error) {
-	apiUrl := fmt.Sprintf("%s/api/v2/transfer/downloadLimit", c.config.Address)
-	result, err := c.doRequest(&requestData{
+	return c.GetGlobalDownloadLimitContext(context.Background())
+}
+
+func (c *client) GetGlobalDownloadLimitContext(ctx context.Context) (string, error) {
+	apiUrl := c.apiURL("transfer/downloadLimit", nil)
+	result, err := c.doRequest(ctx, &requestData{
 		url: apiUrl,
 	})
 	if err != nil {
@@ -162,15 +206,19 @@ func (c *client) GetGlobalDownloadLimit() (string, error) {
 	}
 
 	if result.code != 200 {
-		return "", errors.New("get global download limit failed: " + string(result.body))
+		return "", newAPIError("get global download limit failed", result)
 	}
 
 	return string(result.body), nil
 }
 
 func (c *client) SetGlobalDownloadLimit(limit int) error {
-	apiUrl := fmt.Sprintf("%s/api/v2/transfer/setDownloadLimit?limit=%d", c.config.Address, limit)
-	result, err := c.doRequest(&requestData{
+	return c.SetGlobalDownloadLimitContext(context.Background(), limit)
+}
+
+func (c *client) SetGlobalDownloadLimitContext(ctx context.Context, limit int) error {
+	apiUrl := c.apiURL("transfer/setDownloadLimit", url.Values{"limit": {strconv.Itoa(limit)}})
+	result, err := c.doRequest(ctx, &requestData{
 		url: apiUrl,
 	})
 	if err != nil {
@@ -178,7 +226,7 @@ func (c *client) SetGlobalDownloadLimit(limit int) error {
 	}
 
 	if result.code != 200 {
-		return errors.New("set global download limit failed: " + string(result.body))
+		return newAPIError("set global download limit failed", result)
 	}
 
 	return nil
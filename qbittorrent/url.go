@@ -0,0 +1,66 @@
+package qbittorrent
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// unixSocketHost default host of the requests sent over a unix socket
+const unixSocketHost = "localhost"
+
+// parseAddress validate Config.Address and returns the base url of the WebUI, a trailing slash of the
+// path prefix is removed. for a unix socket address such as unix:///run/qbittorrent.sock?host=qbt.local,
+// the socket path is returned and the base url is http://<host>, the host defaults to localhost
+func parseAddress(address string) (*url.URL, string, error) {
+	if address == "" {
+		return nil, "", errors.New("address is empty")
+	}
+	u, err := url.Parse(address)
+	if err != nil {
+		return nil, "", fmt.Errorf("invalid address %q: %w", address, err)
+	}
+	if u.Scheme == "unix" {
+		return parseUnixAddress(address, u)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, "", fmt.Errorf("invalid address %q: scheme must be http, https or unix", address)
+	}
+	if u.Host == "" {
+		return nil, "", fmt.Errorf("invalid address %q: host is empty", address)
+	}
+	if u.User != nil || u.RawQuery != "" || u.Fragment != "" {
+		return nil, "", fmt.Errorf("invalid address %q: user info, query and fragment are not allowed", address)
+	}
+	u.Path = strings.TrimRight(u.Path, "/")
+	u.RawPath = ""
+	return u, "", nil
+}
+
+func parseUnixAddress(address string, u *url.URL) (*url.URL, string, error) {
+	if u.Host != "" || u.Path == "" {
+		return nil, "", fmt.Errorf("invalid address %q: expected unix:///path/to/socket", address)
+	}
+	query := u.Query()
+	for key := range query {
+		if key != "host" {
+			return nil, "", fmt.Errorf("invalid address %q: unknown parameter %s", address, key)
+		}
+	}
+	var host = unixSocketHost
+	if query.Get("host") != "" {
+		host = query.Get("host")
+	}
+	return &url.URL{Scheme: "http", Host: host}, u.Path, nil
+}
+
+// apiURL returns the url of the webapi endpoint such as "torrents/info", the query is encoded
+func (c *client) apiURL(endpoint string, query url.Values) string {
+	var u = *c.baseURL
+	u.Path = u.Path + "/api/v2/" + endpoint
+	if len(query) != 0 {
+		u.RawQuery = query.Encode()
+	}
+	return u.String()
+}
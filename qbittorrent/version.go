@@ -0,0 +1,155 @@
+package qbittorrent
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Version is a parsed version number such as the WebAPI version "2.9.3"
+type Version struct {
+	Major int
+	Minor int
+	Patch int
+}
+
+// minimum WebAPI versions of the version-dependent endpoints
+var (
+	versionContentLayout  = Version{2, 7, 0}
+	versionTorrentCreator = Version{2, 10, 4}
+	versionStopStart      = Version{2, 11, 0}
+	versionSetTags        = Version{2, 11, 4}
+)
+
+// ParseVersion parse a version such as "2.9.3" or "v4.6.5", missing components are 0
+func ParseVersion(s string) (Version, error) {
+	var parts = strings.Split(strings.TrimPrefix(strings.TrimSpace(s), "v"), ".")
+	if len(parts) > 3 {
+		return Version{}, fmt.Errorf("invalid version %q", s)
+	}
+	var numbers [3]int
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return Version{}, fmt.Errorf("invalid version %q", s)
+		}
+		numbers[i] = n
+	}
+	return Version{Major: numbers[0], Minor: numbers[1], Patch: numbers[2]}, nil
+}
+
+// Compare returns -1, 0 or +1 depending on whether v is lower, equal or higher than other
+func (v Version) Compare(other Version) int {
+	if c := cmp.Compare(v.Major, other.Major); c != 0 {
+		return c
+	}
+	if c := cmp.Compare(v.Minor, other.Minor); c != 0 {
+		return c
+	}
+	return cmp.Compare(v.Patch, other.Patch)
+}
+
+// AtLeast reports whether v is equal or higher than other
+func (v Version) AtLeast(other Version) bool {
+	return v.Compare(other) >= 0
+}
+
+func (v Version) String() string {
+	return fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+}
+
+// Capabilities describes the version-dependent features of the server
+type Capabilities struct {
+	// WebAPIVersion version reported by /api/v2/app/webapiVersion
+	WebAPIVersion Version
+	// TorrentCreator the torrentcreator api is available, WebAPI 2.10.4+
+	TorrentCreator bool
+	// StopStart torrents/stop and torrents/start replace torrents/pause and torrents/resume, WebAPI 2.11.0+
+	StopStart bool
+	// SetTags torrents/setTags is available, WebAPI 2.11.4+
+	SetTags bool
+}
+
+func newCapabilities(version Version) *Capabilities {
+	return &Capabilities{
+		WebAPIVersion:  version,
+		TorrentCreator: version.AtLeast(versionTorrentCreator),
+		StopStart:      version.AtLeast(versionStopStart),
+		SetTags:        version.AtLeast(versionSetTags),
+	}
+}
+
+func (c *client) Capabilities() (*Capabilities, error) {
+	return c.CapabilitiesContext(context.Background())
+}
+
+func (c *client) CapabilitiesContext(ctx context.Context) (*Capabilities, error) {
+	version, err := c.webAPIVersion(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return newCapabilities(version), nil
+}
+
+// webAPIVersion returns the cached WebAPI version, the version is queried once after every login
+func (c *client) webAPIVersion(ctx context.Context) (Version, error) {
+	c.versionMu.Lock()
+	defer c.versionMu.Unlock()
+	if c.version != nil {
+		return *c.version, nil
+	}
+
+	apiUrl := c.apiURL("app/webapiVersion", nil)
+	result, err := c.doRequest(ctx, &requestData{
+		url: apiUrl,
+		// webAPIVersion is called by the login, it must not login again
+		skipReLogin: true,
+	})
+	if err != nil {
+		return Version{}, err
+	}
+
+	if result.code != 200 {
+		return Version{}, newAPIError("get webapi version failed", result)
+	}
+
+	version, err := ParseVersion(string(result.body))
+	if err != nil {
+		return Version{}, err
+	}
+	c.version = &version
+	return version, nil
+}
+
+// requireVersion returns an UnsupportedError if the server is older than the version
+func (c *client) requireVersion(ctx context.Context, feature string, required Version) error {
+	version, err := c.webAPIVersion(ctx)
+	if err != nil {
+		return err
+	}
+	if !version.AtLeast(required) {
+		return &UnsupportedError{Feature: feature, Required: required, Actual: version}
+	}
+	return nil
+}
+
+// endpointByVersion returns newer if the server is at least the version, otherwise older
+func (c *client) endpointByVersion(ctx context.Context, version Version, newer, older string) (string, error) {
+	current, err := c.webAPIVersion(ctx)
+	if err != nil {
+		return "", err
+	}
+	if current.AtLeast(version) {
+		return newer, nil
+	}
+	return older, nil
+}
+
+// resetVersion forget the cached version so that it is queried again, used after login
+func (c *client) resetVersion() {
+	c.versionMu.Lock()
+	defer c.versionMu.Unlock()
+	c.version = nil
+}
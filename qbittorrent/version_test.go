@@ -0,0 +1,71 @@
+package qbittorrent
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/xuthus5/qbittorrent-client-go/qbittorrent/qbittest"
+)
+
+func TestParseVersion(t *testing.T) {
+	for s, expected := range map[string]Version{
+		"2.9.3":    {2, 9, 3},
+		"v4.6.5":   {4, 6, 5},
+		"2.11":     {2, 11, 0},
+		"2.11.4\n": {2, 11, 4},
+	} {
+		version, err := ParseVersion(s)
+		if err != nil || version != expected {
+			t.Fatalf("ParseVersion(%q) = %v, %v", s, version, err)
+		}
+	}
+	if _, err := ParseVersion("2.x"); err == nil {
+		t.Fatal("expected an error for an invalid version")
+	}
+	if !(Version{2, 11, 0}).AtLeast(Version{2, 10, 4}) || (Version{2, 9, 3}).AtLeast(Version{2, 10, 0}) {
+		t.Fatal("unexpected version order")
+	}
+}
+
+func TestClient_Capabilities(t *testing.T) {
+	client, server := newTestClient(t)
+	server.AddTorrent(&qbittest.Torrent{Hash: "8c212779b4abde7c6bc608063a0d008b7e40ce32", State: "downloading"})
+
+	capabilities, err := client.Capabilities()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if capabilities.WebAPIVersion.String() != qbittest.DefaultWebAPIVersion || capabilities.StopStart || capabilities.SetTags {
+		t.Fatalf("unexpected capabilities: %+v", capabilities)
+	}
+	if err := client.Torrent().SetTags([]string{"8c212779b4abde7c6bc608063a0d008b7e40ce32"}, []string{"a"}); !errors.Is(err, ErrUnsupported) {
+		t.Fatalf("expected ErrUnsupported, got %v", err)
+	}
+	if err := client.Torrent().PauseTorrents([]string{"8c212779b4abde7c6bc608063a0d008b7e40ce32"}); err != nil {
+		t.Fatal(err)
+	}
+	if torrent, _ := server.Torrent("8c212779b4abde7c6bc608063a0d008b7e40ce32"); torrent.State != "pausedDL" {
+		t.Fatalf("expected the torrent to be paused, got %s", torrent.State)
+	}
+
+	// the version is detected again after login
+	server.SetVersion("v5.1.0", "2.11.4")
+	if err := client.Authentication().Login(); err != nil {
+		t.Fatal(err)
+	}
+	if capabilities, err = client.Capabilities(); err != nil || !capabilities.StopStart || !capabilities.SetTags {
+		t.Fatalf("unexpected capabilities: %+v, %v", capabilities, err)
+	}
+	if err := client.Torrent().ResumeTorrents([]string{"8c212779b4abde7c6bc608063a0d008b7e40ce32"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.Torrent().PauseTorrents([]string{"8c212779b4abde7c6bc608063a0d008b7e40ce32"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.Torrent().SetTags([]string{"8c212779b4abde7c6bc608063a0d008b7e40ce32"}, []string{"a", "b"}); err != nil {
+		t.Fatal(err)
+	}
+	if torrent, _ := server.Torrent("8c212779b4abde7c6bc608063a0d008b7e40ce32"); torrent.State != "stoppedDL" || len(torrent.Tags) != 2 {
+		t.Fatalf("unexpected torrent: %s %v", torrent.State, torrent.Tags)
+	}
+}
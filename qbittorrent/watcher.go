@@ -0,0 +1,233 @@
+package qbittorrent
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	defaultWatchInterval   = 2 * time.Second
+	defaultWatchBufferSize = 64
+)
+
+// ErrWatcherStarted is returned by Watcher.Run when the watcher was already run
+var ErrWatcherStarted = errors.New("watcher already started")
+
+type EventType string
+
+const (
+	EventTorrentAdded      EventType = "torrent_added"      // a torrent was added
+	EventTorrentRemoved    EventType = "torrent_removed"    // a torrent was removed
+	EventDownloadCompleted EventType = "download_completed" // a torrent finished downloading
+	EventStateChanged      EventType = "state_changed"      // the state of a torrent changed
+	EventCategoryChanged   EventType = "category_changed"   // the category of a torrent changed
+	EventTagsChanged       EventType = "tags_changed"       // the tags of a torrent changed
+	EventTrackerErrored    EventType = "tracker_errored"    // a torrent lost its last working tracker
+)
+
+// Event is a change of a torrent detected by Watcher
+type Event struct {
+	Type EventType
+	Hash string
+	// Torrent current torrent, the last known torrent for EventTorrentRemoved
+	Torrent *TorrentInfo
+	// Previous torrent before the change, nil for EventTorrentAdded
+	Previous *TorrentInfo
+}
+
+// WatcherOption configures a Watcher created by NewWatcher
+type WatcherOption struct {
+	Interval time.Duration // interval between two polls, default 2s
+	// BufferSize capacity of the Events channel, default 64
+	BufferSize int
+	// DropEvents drop the events that do not fit in the Events channel instead of waiting for the
+	// reader, dropped events are counted by Watcher.Dropped
+	DropEvents bool
+	// EmitInitial emit EventTorrentAdded for the torrents found by the first poll
+	EmitInitial bool
+	// OnError is called when a poll failed, the watcher keeps polling
+	OnError func(err error)
+}
+
+// Watcher polls /api/v2/sync/maindata and reports the changes of the torrents as events, either
+// on the Events channel or to the callbacks registered with OnEvent
+type Watcher struct {
+	syncer *Syncer
+	opt    WatcherOption
+
+	mu       sync.Mutex
+	handlers []func(Event)
+	events   chan Event
+	started  bool
+	stopped  bool
+	dropped  atomic.Uint64
+}
+
+// NewWatcher create a Watcher polling s, opt may be nil
+func NewWatcher(s Sync, opt *WatcherOption) *Watcher {
+	var w = &Watcher{syncer: NewSyncer(s)}
+	if opt != nil {
+		w.opt = *opt
+	}
+	if w.opt.Interval <= 0 {
+		w.opt.Interval = defaultWatchInterval
+	}
+	if w.opt.BufferSize <= 0 {
+		w.opt.BufferSize = defaultWatchBufferSize
+	}
+	return w
+}
+
+// OnEvent register a callback, callbacks are called in order from the polling goroutine, so a
+// slow callback delays the next poll
+func (w *Watcher) OnEvent(handler func(Event)) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.handlers = append(w.handlers, handler)
+}
+
+// Events returns the channel of the events, the channel is closed when Run returns, so ranging
+// over it only ends once Run was started and stopped. Events are only sent to the channel once it
+// has been requested, the channel requested after Run returned is already closed.
+func (w *Watcher) Events() <-chan Event {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.events == nil {
+		w.events = make(chan Event, w.opt.BufferSize)
+		if w.stopped {
+			close(w.events)
+		}
+	}
+	return w.events
+}
+
+// Dropped returns the number of events dropped because the Events channel was full
+func (w *Watcher) Dropped() uint64 {
+	return w.dropped.Load()
+}
+
+// State returns a copy of the state of the last poll
+func (w *Watcher) State() *SyncState {
+	return w.syncer.Snapshot()
+}
+
+// Run poll until ctx is canceled, it returns ctx.Err() and closes the Events channel. Run may only
+// be called once, the later calls return ErrWatcherStarted
+func (w *Watcher) Run(ctx context.Context) error {
+	w.mu.Lock()
+	if w.started {
+		w.mu.Unlock()
+		return ErrWatcherStarted
+	}
+	w.started = true
+	w.mu.Unlock()
+	defer func() {
+		w.mu.Lock()
+		defer w.mu.Unlock()
+		w.stopped = true
+		if w.events != nil {
+			close(w.events)
+		}
+	}()
+
+	var previous *SyncState
+	var ticker = time.NewTicker(w.opt.Interval)
+	defer ticker.Stop()
+	for {
+		if err := w.syncer.Sync(ctx); err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			if w.opt.OnError != nil {
+				w.opt.OnError(err)
+			}
+		} else {
+			current := w.syncer.Snapshot()
+			if previous != nil || w.opt.EmitInitial {
+				for _, event := range diffStates(previous, current) {
+					if err := w.emit(ctx, event); err != nil {
+						return err
+					}
+				}
+			}
+			previous = current
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// emit deliver an event to the callbacks and the channel
+func (w *Watcher) emit(ctx context.Context, event Event) error {
+	w.mu.Lock()
+	var handlers, events = w.handlers, w.events
+	w.mu.Unlock()
+	for _, handler := range handlers {
+		handler(event)
+	}
+	if events == nil {
+		return nil
+	}
+	if w.opt.DropEvents {
+		select {
+		case events <- event:
+		default:
+			w.dropped.Add(1)
+		}
+		return nil
+	}
+	select {
+	case events <- event:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// diffStates returns the events turning previous into current, previous may be nil
+func diffStates(previous, current *SyncState) []Event {
+	var before = make(map[string]*TorrentInfo)
+	if previous != nil {
+		before = previous.Torrents
+	}
+	var events []Event
+	for _, hash := range sortedKeys(current.Torrents) {
+		torrent := current.Torrents[hash]
+		old, ok := before[hash]
+		if !ok {
+			events = append(events, Event{Type: EventTorrentAdded, Hash: hash, Torrent: torrent})
+			continue
+		}
+		if old.State != torrent.State {
+			events = append(events, Event{Type: EventStateChanged, Hash: hash, Torrent: torrent, Previous: old})
+		}
+		if old.Progress < 1 && torrent.Progress >= 1 {
+			events = append(events, Event{Type: EventDownloadCompleted, Hash: hash, Torrent: torrent, Previous: old})
+		}
+		if old.Category != torrent.Category {
+			events = append(events, Event{Type: EventCategoryChanged, Hash: hash, Torrent: torrent, Previous: old})
+		}
+		if old.Tags != torrent.Tags {
+			events = append(events, Event{Type: EventTagsChanged, Hash: hash, Torrent: torrent, Previous: old})
+		}
+		// the tracker field holds the current working tracker, it is cleared when all trackers failed
+		// but also while the torrent is paused, stopped or queued
+		if old.Tracker != "" && torrent.Tracker == "" && torrent.TrackersCount > 0 &&
+			!torrent.State.IsPaused() && !torrent.State.IsQueued() {
+			events = append(events, Event{Type: EventTrackerErrored, Hash: hash, Torrent: torrent, Previous: old})
+		}
+	}
+	for _, hash := range sortedKeys(before) {
+		if _, ok := current.Torrents[hash]; !ok {
+			events = append(events, Event{Type: EventTorrentRemoved, Hash: hash, Torrent: before[hash], Previous: before[hash]})
+		}
+	}
+	return events
+}
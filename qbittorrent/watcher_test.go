@@ -0,0 +1,137 @@
+package qbittorrent
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/xuthus5/qbittorrent-client-go/qbittorrent/qbittest"
+)
+
+func TestWatcher(t *testing.T) {
+	client, server := newTestClient(t)
+	server.AddTorrent(&qbittest.Torrent{
+		Hash:     "8c212779b4abde7c6bc608063a0d008b7e40ce32",
+		Name:     "ubuntu-24.04-desktop-amd64.iso",
+		State:    "downloading",
+		Progress: 0.5,
+		Trackers: []*qbittest.Tracker{{URL: "https://torrent.ubuntu.com/announce", Status: 2}},
+	})
+
+	watcher := NewWatcher(client.Sync(), &WatcherOption{Interval: 10 * time.Millisecond})
+	var handled []EventType
+	watcher.OnEvent(func(event Event) {
+		handled = append(handled, event.Type)
+	})
+	events := watcher.Events()
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		done <- watcher.Run(ctx)
+	}()
+	// wait for the first poll, the existing torrents are not reported
+	for watcher.State().Rid == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	server.UpdateTorrent("8c212779b4abde7c6bc608063a0d008b7e40ce32", func(torrent *qbittest.Torrent) {
+		torrent.State = "uploading"
+		torrent.Progress = 1
+		torrent.Category = "linux"
+		torrent.Trackers[0].Status = 4
+	})
+	server.AddTorrent(&qbittest.Torrent{Hash: "d1f0ec5b4ab3a0c5aa8e2b0f3c1d6b4cf0a6a3d2", Name: "debian-12.5.0-amd64-netinst.iso"})
+	var expected = []EventType{EventTorrentAdded, EventStateChanged, EventDownloadCompleted, EventCategoryChanged, EventTrackerErrored}
+	var received = make(map[EventType]Event)
+	for len(received) < len(expected) {
+		select {
+		case event := <-events:
+			received[event.Type] = event
+		case <-time.After(time.Second):
+			t.Fatalf("timed out waiting for events, received %v", received)
+		}
+	}
+	if event := received[EventStateChanged]; event.Previous.State != "downloading" || event.Torrent.State != "uploading" {
+		t.Fatalf("unexpected state change: %s -> %s", event.Previous.State, event.Torrent.State)
+	}
+
+	server.RemoveTorrent("d1f0ec5b4ab3a0c5aa8e2b0f3c1d6b4cf0a6a3d2")
+	select {
+	case event := <-events:
+		if event.Type != EventTorrentRemoved || event.Hash != "d1f0ec5b4ab3a0c5aa8e2b0f3c1d6b4cf0a6a3d2" {
+			t.Fatalf("unexpected event: %+v", event)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for the removed event")
+	}
+
+	cancel()
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context canceled, got %v", err)
+	}
+	if _, ok := <-events; ok {
+		t.Fatal("events channel was not closed")
+	}
+	if len(handled) != len(expected)+1 {
+		t.Fatalf("expected callbacks for every event, got %v", handled)
+	}
+}
+
+func TestWatcher_DropEvents(t *testing.T) {
+	client, server := newTestClient(t)
+	server.AddTorrent(&qbittest.Torrent{Hash: "8c212779b4abde7c6bc608063a0d008b7e40ce32"})
+	server.AddTorrent(&qbittest.Torrent{Hash: "d1f0ec5b4ab3a0c5aa8e2b0f3c1d6b4cf0a6a3d2"})
+
+	watcher := NewWatcher(client.Sync(), &WatcherOption{BufferSize: 1, DropEvents: true, EmitInitial: true})
+	events := watcher.Events()
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if err := watcher.Run(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatal(err)
+	}
+	if event := <-events; event.Type != EventTorrentAdded || watcher.Dropped() != 1 {
+		t.Fatalf("expected one buffered and one dropped event, got %+v and %d dropped", event, watcher.Dropped())
+	}
+}
+
+func TestWatcher_RunOnce(t *testing.T) {
+	client, _ := newTestClient(t)
+	watcher := NewWatcher(client.Sync(), nil)
+	events := watcher.Events()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := watcher.Run(ctx); !errors.Is(err, context.Canceled) {
+		t.Fatal(err)
+	}
+	if err := watcher.Run(context.Background()); !errors.Is(err, ErrWatcherStarted) {
+		t.Fatalf("expected ErrWatcherStarted, got %v", err)
+	}
+	for range events {
+	}
+	// the channel requested after Run returned is closed too
+	if _, ok := <-watcher.Events(); ok {
+		t.Fatal("expected a closed channel")
+	}
+}
+
+func TestDiffStates_Paused(t *testing.T) {
+	const hash = "8c212779b4abde7c6bc608063a0d008b7e40ce32"
+	running := &TorrentInfo{Hash: hash, State: StateDownloading, Tracker: "https://torrent.ubuntu.com/announce", TrackersCount: 1}
+	for _, state := range []TorrentState{StatePausedDL, StateStoppedDL, StateQueuedDL} {
+		current := &TorrentInfo{Hash: hash, State: state, TrackersCount: 1}
+		events := diffStates(&SyncState{Torrents: map[string]*TorrentInfo{hash: running}},
+			&SyncState{Torrents: map[string]*TorrentInfo{hash: current}})
+		if len(events) != 1 || events[0].Type != EventStateChanged {
+			t.Fatalf("%s: expected only a state change, got %+v", state, events)
+		}
+	}
+
+	// the tracker is cleared while the state stays the same
+	failed := &TorrentInfo{Hash: hash, State: StateDownloading, TrackersCount: 1}
+	events := diffStates(&SyncState{Torrents: map[string]*TorrentInfo{hash: running}},
+		&SyncState{Torrents: map[string]*TorrentInfo{hash: failed}})
+	if len(events) != 1 || events[0].Type != EventTrackerErrored {
+		t.Fatalf("expected a tracker error, got %+v", events)
+	}
+}